@@ -0,0 +1,86 @@
+package tourist
+
+import "time"
+
+// Event is implemented by every structured lifecycle event a Tourist emits.
+// Consumers type-switch on the concrete type to tell them apart; see
+// AddListener and Subscribe in event_bus.go for how to receive them.
+type Event interface {
+	isEvent()
+}
+
+// TourStartedEvent fires when a worker picks tr up for its Attempt'th
+// Visit.
+type TourStartedEvent struct {
+	Tour    string
+	Attempt int
+	At      time.Time
+}
+
+func (TourStartedEvent) isEvent() {}
+
+// TourProgressEvent reports the run's overall completion percentage right
+// after Tour succeeds or is dead-lettered. A Tour resolved as canceled emits
+// no TourProgressEvent.
+type TourProgressEvent struct {
+	Tour    string
+	Percent float64 // 0..100, Finished/Total of the event that triggered it
+}
+
+func (TourProgressEvent) isEvent() {}
+
+// TourSucceededEvent fires when a Visit returns a nil error.
+type TourSucceededEvent struct {
+	Tour            string
+	Finished, Total int
+}
+
+func (TourSucceededEvent) isEvent() {}
+
+// TourRetriedEvent fires when a failed Visit is scheduled to rejoin the
+// work queue after a backoff delay, rather than dead-lettered. NextAt is
+// the earliest time the Tour will be retried.
+type TourRetriedEvent struct {
+	Tour    string
+	Attempt int
+	NextAt  time.Time
+}
+
+func (TourRetriedEvent) isEvent() {}
+
+// TourFailedEvent fires when a Tour is dead-lettered: a BadTargetClassifier
+// flagged it, or it exhausted MaxRetries. Err is the error from the last
+// Visit attempt.
+type TourFailedEvent struct {
+	Tour            string
+	Err             error
+	Finished, Total int
+}
+
+func (TourFailedEvent) isEvent() {}
+
+// TourCanceledEvent fires when Cancel stops a Tour before, during, or
+// while it waits out a retry backoff.
+type TourCanceledEvent struct {
+	Tour            string
+	Finished, Total int
+}
+
+func (TourCanceledEvent) isEvent() {}
+
+// TouristFinishedEvent fires once per run, after every Tour has reached a
+// terminal state and just before Status becomes TouristFinished.
+type TouristFinishedEvent struct {
+	Finished, Total int
+}
+
+func (TouristFinishedEvent) isEvent() {}
+
+// percentOf returns finished as a percentage of total, or 0 if total is
+// not yet known.
+func percentOf(finished, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * float64(finished) / float64(total)
+}
@@ -0,0 +1,216 @@
+package tourist
+
+import "sync/atomic"
+
+const (
+	eventQueueSize      = 256 // the dispatcher's central inbox
+	subscriberQueueSize = 64  // per-Subscribe bounded buffer
+)
+
+// listenerEntry is one AddListener registration.
+type listenerEntry struct {
+	id int
+	l  Listener
+}
+
+// subscriber is one Subscribe registration. The dispatcher never sends to
+// ch directly: it offers the Event to buf, dropping the oldest queued
+// Event (and counting the drop) if buf is full, while a dedicated
+// forwarder goroutine drains buf into ch. That way a consumer slow to
+// drain ch only ever delays itself, never the dispatcher or the workers
+// feeding it.
+type subscriber struct {
+	id      int
+	ch      chan<- Event
+	buf     chan Event
+	dropped int64 // atomic
+}
+
+// offer enqueues ev for delivery, evicting the oldest buffered Event (and
+// incrementing dropped) if the buffer is full. It is only ever called by
+// the single dispatcher goroutine, so the evict-then-send retry below
+// never races with another writer.
+func (s *subscriber) offer(ev Event) {
+	for {
+		select {
+		case s.buf <- ev:
+			return
+		default:
+			select {
+			case <-s.buf:
+				atomic.AddInt64(&s.dropped, 1)
+			default:
+			}
+		}
+	}
+}
+
+// forward drains buf into ch until stop is closed.
+func (s *subscriber) forward(stop <-chan struct{}) {
+	for {
+		select {
+		case ev := <-s.buf:
+			select {
+			case s.ch <- ev:
+			case <-stop:
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// flushMarker is an internal Event used only to synchronize with the
+// dispatcher goroutine: once the dispatcher processes one, every Event
+// emitted before it is guaranteed to have already been delivered. It is
+// never fanned out to listeners or subscribers.
+type flushMarker struct {
+	done chan struct{}
+}
+
+func (flushMarker) isEvent() {}
+
+// dispatch is the single long-lived goroutine (one per Tourist, started by
+// New) that fans events out to AddListener listeners and Subscribe
+// channels, decoupling slow consumers from the workers emitting events.
+func (t *Tourist) dispatch() {
+	for ev := range t.events {
+		if fm, ok := ev.(flushMarker); ok {
+			close(fm.done)
+			continue
+		}
+
+		t.mu.Lock()
+		listeners := append([]listenerEntry(nil), t.listeners...)
+		subs := append([]*subscriber(nil), t.subs...)
+		t.mu.Unlock()
+
+		for _, le := range listeners {
+			notifyListener(le.l, ev)
+		}
+		for _, s := range subs {
+			s.offer(ev)
+		}
+	}
+}
+
+// notifyListener adapts a structured Event onto the legacy Listener
+// interface: OnNoticed fires for every terminal outcome, exactly as it did
+// before Event existed.
+func notifyListener(l Listener, ev Event) {
+	switch e := ev.(type) {
+	case TourSucceededEvent:
+		l.OnNoticed(Tour{name: e.Tour, status: TourCompleted}, e.Finished, e.Total)
+	case TourFailedEvent:
+		l.OnNoticed(Tour{name: e.Tour, status: TourError}, e.Finished, e.Total)
+	case TourCanceledEvent:
+		l.OnNoticed(Tour{name: e.Tour, status: TourCanceled}, e.Finished, e.Total)
+	}
+}
+
+// emit offers ev to the dispatcher without blocking: if the inbox is full
+// the event is dropped so a slow or stuck listener can never stall a
+// worker. It is used for the high-frequency per-Tour events emitted from
+// the worker goroutines.
+func (t *Tourist) emit(ev Event) {
+	select {
+	case t.events <- ev:
+	default:
+	}
+}
+
+// emitSync delivers ev to the dispatcher and blocks until it has been
+// fanned out to every listener and subscriber. It is used for the one
+// event per run (TouristFinishedEvent) where callers rely on the legacy
+// OnNoticed having already fired for every Tour before Status reports
+// TouristFinished.
+func (t *Tourist) emitSync(ev Event) {
+	t.events <- ev
+	t.flush()
+}
+
+// flush blocks until the dispatcher has processed every Event enqueued
+// before this call, so callers that emitted via the non-blocking emit can
+// still rely on delivery having happened by the time flush returns. Cancel
+// uses this to preserve the pre-Event contract that a Listener passed to
+// Start has already been notified of every Tour by the time Cancel
+// returns.
+func (t *Tourist) flush() {
+	done := make(chan struct{})
+	t.events <- flushMarker{done: done}
+	<-done
+}
+
+// AddListener registers l to receive OnNoticed for every Tour that reaches
+// a terminal outcome (succeeded, failed, or canceled) in this run and any
+// future one, until the returned func is called. Unlike the listener
+// passed to Start, a Listener added this way survives across
+// Reset/Enter/Start cycles.
+func AddListener(l Listener) (unsubscribe func()) {
+	return t.AddListener(l)
+}
+
+func (t *Tourist) AddListener(l Listener) (unsubscribe func()) {
+	if l == nil {
+		return func() {}
+	}
+	t.mu.Lock()
+	unsubscribe = t.addListenerLocked(l)
+	t.mu.Unlock()
+	return unsubscribe
+}
+
+// addListenerLocked is AddListener's body for callers that already hold
+// t.mu, such as Start registering the Listener passed to it.
+func (t *Tourist) addListenerLocked(l Listener) (unsubscribe func()) {
+	id := t.nextListenerID
+	t.nextListenerID++
+	t.listeners = append(t.listeners, listenerEntry{id: id, l: l})
+
+	return func() {
+		t.mu.Lock()
+		for i, le := range t.listeners {
+			if le.id == id {
+				t.listeners = append(t.listeners[:i], t.listeners[i+1:]...)
+				break
+			}
+		}
+		t.mu.Unlock()
+	}
+}
+
+// Subscribe registers ch to receive every structured Event this Tourist
+// emits, across this run and any future one, until the returned func is
+// called. The dispatcher never blocks sending to ch: ch's effective queue
+// is bounded, and the oldest pending Event is dropped to make room for a
+// new one when a consumer falls behind, so a stuck subscriber can never
+// stall a worker or another subscriber.
+func Subscribe(ch chan<- Event) (unsubscribe func()) {
+	return t.Subscribe(ch)
+}
+
+func (t *Tourist) Subscribe(ch chan<- Event) (unsubscribe func()) {
+	sub := &subscriber{ch: ch, buf: make(chan Event, subscriberQueueSize)}
+	stop := make(chan struct{})
+	go sub.forward(stop)
+
+	t.mu.Lock()
+	id := t.nextSubID
+	t.nextSubID++
+	sub.id = id
+	t.subs = append(t.subs, sub)
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		for i, s := range t.subs {
+			if s.id == id {
+				t.subs = append(t.subs[:i], t.subs[i+1:]...)
+				break
+			}
+		}
+		t.mu.Unlock()
+		close(stop)
+	}
+}
@@ -0,0 +1,130 @@
+package tourist
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddListenerReceivesOnNoticedLikeTheStartListener(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(2))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	added := &collectingListener{}
+	unsubscribe := tourist.AddListener(added)
+	defer unsubscribe()
+
+	if err := tourist.Start(stubVisitor{}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	if got := added.count(); got != 2 {
+		t.Fatalf("AddListener-registered listener saw %d Tours, want 2", got)
+	}
+}
+
+func TestAddListenerSurvivesAcrossRuns(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+
+	added := &collectingListener{}
+	unsubscribe := tourist.AddListener(added)
+	defer unsubscribe()
+
+	for _, names := range [][]string{{"a"}, {"b", "c"}} {
+		if err := tourist.Enter("testdata", stubChecker{names: names}); err != nil {
+			t.Fatalf("Enter: %v", err)
+		}
+		if err := tourist.Start(stubVisitor{}, nil); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		waitForFinished(t, tourist)
+	}
+
+	if got := added.count(); got != 3 {
+		t.Fatalf("AddListener-registered listener saw %d Tours across both runs, want 3", got)
+	}
+}
+
+func TestSubscribeReceivesTheFullTourLifecycle(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	events := make(chan Event, 16)
+	unsubscribe := tourist.Subscribe(events)
+	defer unsubscribe()
+
+	if err := tourist.Start(stubVisitor{}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	var sawStarted, sawSucceeded, sawFinished bool
+	deadline := time.After(time.Second)
+	for !sawFinished {
+		select {
+		case ev := <-events:
+			switch ev.(type) {
+			case TourStartedEvent:
+				sawStarted = true
+			case TourSucceededEvent:
+				sawSucceeded = true
+			case TouristFinishedEvent:
+				sawFinished = true
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for the full event lifecycle; started=%v succeeded=%v finished=%v",
+				sawStarted, sawSucceeded, sawFinished)
+		}
+	}
+	if !sawStarted || !sawSucceeded {
+		t.Fatalf("missing expected events: started=%v succeeded=%v", sawStarted, sawSucceeded)
+	}
+}
+
+func TestSubscribeDropsOldestWhenASlowConsumerFallsBehind(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(4))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	names := make([]string, 0, subscriberQueueSize*2)
+	for i := 0; i < subscriberQueueSize*2; i++ {
+		names = append(names, string(rune('a'+i%26))+string(rune('0'+i/26)))
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: names}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	// An unbuffered, never-read channel: every offer must fall back to the
+	// evict-then-send path instead of blocking the dispatcher.
+	events := make(chan Event)
+	unsubscribe := tourist.Subscribe(events)
+	defer unsubscribe()
+
+	if err := tourist.Start(stubVisitor{}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	// Far more than subscriberQueueSize+1 events reached the dispatcher, so
+	// the subscriber's buffer must have evicted some.
+	tourist.mu.Lock()
+	subs := append([]*subscriber(nil), tourist.subs...)
+	tourist.mu.Unlock()
+	if len(subs) != 1 || atomic.LoadInt64(&subs[0].dropped) == 0 {
+		t.Fatalf("expected the slow subscriber to have dropped events, subs=%d", len(subs))
+	}
+}
@@ -0,0 +1,122 @@
+package ctlsrv_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sharework-cn/smtools/common/sources/park"
+	"github.com/sharework-cn/smtools/common/sources/park/ctlsrv"
+)
+
+func newRunningPark(t *testing.T) (*park.Park[int], chan int) {
+	t.Helper()
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int]([]func(int) error{func(int) error { return nil }}),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int, 4)
+	if err := p.Start(context.Background(), data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return p, data
+}
+
+func TestStatusAndHealthzReflectARunningPark(t *testing.T) {
+	p, data := newRunningPark(t)
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, time.Second, 0).Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /healthz on a live Park, got %d", resp.StatusCode)
+	}
+
+	data <- 1
+	time.Sleep(50 * time.Millisecond)
+
+	resp, err = http.Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatalf("GET /status: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /status, got %d", resp.StatusCode)
+	}
+}
+
+// TestStatusReportsChannelStatusAndInFlight checks that GET /status surfaces
+// the Park's per-channel RoutineStatus and in-flight Tour count, not just
+// the aggregate counters.
+func TestStatusReportsChannelStatusAndInFlight(t *testing.T) {
+	p, data := newRunningPark(t)
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, time.Second, 0).Handler())
+	defer srv.Close()
+
+	data <- 1
+
+	var report ctlsrv.StatusReport
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := http.Get(srv.URL + "/status")
+		if err != nil {
+			t.Fatalf("GET /status: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 from /status, got %d", resp.StatusCode)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("decode /status body: %v", err)
+		}
+		resp.Body.Close()
+		if cs, ok := report.ChannelStatus[0]; ok && cs.Status == park.RsReady {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("channel 0 never reported RsReady via /status, last report: %+v", report)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(report.ChannelStatus) != 1 {
+		t.Fatalf("expected 1 channel in channel_status, got %d", len(report.ChannelStatus))
+	}
+}
+
+func TestPauseResumeCancelViaHTTP(t *testing.T) {
+	p, _ := newRunningPark(t)
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, time.Second, 0).Handler())
+	defer srv.Close()
+
+	if resp, err := http.Post(srv.URL+"/pause", "", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /pause: resp=%v err=%v", resp, err)
+	}
+	if p.Status() != park.ParkPaused {
+		t.Fatalf("expected ParkPaused after /pause, got %v", p.Status())
+	}
+
+	if resp, err := http.Post(srv.URL+"/resume", "", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /resume: resp=%v err=%v", resp, err)
+	}
+	if p.Status() != park.Open {
+		t.Fatalf("expected Open after /resume, got %v", p.Status())
+	}
+
+	if resp, err := http.Post(srv.URL+"/cancel", "", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /cancel: resp=%v err=%v", resp, err)
+	}
+	if p.Status() != park.ParkAborted {
+		t.Fatalf("expected ParkAborted after /cancel, got %v", p.Status())
+	}
+}
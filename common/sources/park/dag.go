@@ -0,0 +1,370 @@
+package park
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrCycle is returned by IsSafe when a stage lists itself or one of its
+	// own ancestors as a parent.
+	ErrCycle = stderrors.New("cycle detected in stage graph")
+	// ErrCollision is recorded on a Tour that re-enters a stage it has
+	// already been processed by; a correctly validated DAG never triggers
+	// this, so seeing it means the topology was built or forwarded wrong.
+	ErrCollision = stderrors.New("tour re-entered an already-visited stage")
+)
+
+// Stage is one node in a channel's execution DAG, built with WithStages.
+// Parents names the stages whose output feeds this stage's input; a stage
+// with no Parents is an entry point fed directly from the channel's data
+// queue, and a stage with no children is a sink whose output lands on the
+// Park's post-processing queue.
+type Stage[T any] struct {
+	ID      string
+	Fn      func(T) error
+	Parents []string
+}
+
+// IsSafe validates a stage graph, rejecting self-references and cycles.
+func IsSafe[T any](stages []Stage[T]) error {
+	byID := make(map[string]Stage[T], len(stages))
+	for _, s := range stages {
+		if _, dup := byID[s.ID]; dup {
+			return errors.Wrapf(ErrCycle, "duplicate stage id %q", s.ID)
+		}
+		byID[s.ID] = s
+	}
+	for _, s := range stages {
+		for _, parent := range s.Parents {
+			if parent == s.ID {
+				return errors.Wrapf(ErrCycle, "stage %q lists itself as a parent", s.ID)
+			}
+			if _, ok := byID[parent]; !ok {
+				return errors.Wrapf(ErrArg, "stage %q has unknown parent %q", s.ID, parent)
+			}
+		}
+	}
+
+	const (
+		white = iota
+		gray
+		black
+	)
+	color := make(map[string]int, len(stages))
+	var visit func(id string) error
+	visit = func(id string) error {
+		color[id] = gray
+		for _, parent := range byID[id].Parents {
+			switch color[parent] {
+			case gray:
+				return errors.Wrapf(ErrCycle, "%q is its own ancestor via %q", id, parent)
+			case white:
+				if err := visit(parent); err != nil {
+					return err
+				}
+			}
+		}
+		color[id] = black
+		return nil
+	}
+	for _, s := range stages {
+		if color[s.ID] == white {
+			if err := visit(s.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// topoSort orders stages so that every stage comes after all of its
+// parents, using Kahn's algorithm. Callers are expected to have validated
+// the graph with IsSafe first.
+func topoSort[T any](stages []Stage[T]) ([]Stage[T], error) {
+	byID := make(map[string]Stage[T], len(stages))
+	indegree := make(map[string]int, len(stages))
+	children := make(map[string][]string, len(stages))
+	for _, s := range stages {
+		byID[s.ID] = s
+		indegree[s.ID] = len(s.Parents)
+	}
+	for _, s := range stages {
+		for _, parent := range s.Parents {
+			children[parent] = append(children[parent], s.ID)
+		}
+	}
+
+	var queue []string
+	for _, s := range stages {
+		if indegree[s.ID] == 0 {
+			queue = append(queue, s.ID)
+		}
+	}
+
+	sorted := make([]Stage[T], 0, len(stages))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, byID[id])
+		for _, c := range children[id] {
+			indegree[c]--
+			if indegree[c] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+	if len(sorted) != len(stages) {
+		return nil, errors.Wrap(ErrCycle, "stage graph has a cycle")
+	}
+	return sorted, nil
+}
+
+// WithStages configures conf to run a DAG of stages instead of the legacy
+// linear chain set by WithFuncs; the two are mutually exclusive, and
+// WithStages takes precedence when both are set.
+func WithStages[T any](stages []Stage[T]) Optf[T] {
+	return func(conf *Conf[T]) error {
+		if len(stages) > MaxFunctions {
+			return ErrArg
+		}
+		if err := IsSafe(stages); err != nil {
+			return err
+		}
+		conf.stages = stages
+		return nil
+	}
+}
+
+func cloneTour[T any](v Tour[T]) Tour[T] {
+	cp := v
+	cp.visited = make(map[string]struct{}, len(v.visited))
+	for k := range v.visited {
+		cp.visited[k] = struct{}{}
+	}
+	return cp
+}
+
+// newDAGChannel runs conf.stages as a DAG: every stage with no parents is
+// fed directly from queue, a stage with multiple parents fans in from all
+// of them, and a stage with multiple children fans its result out to each.
+func (p *Park[T]) newDAGChannel(ctx context.Context, id int, queue <-chan T) {
+	go func(id int) {
+		sorted, err := topoSort(p.conf.stages)
+		if err != nil {
+			// WithStages already validated this graph with IsSafe; a
+			// failure here means the Conf was mutated after validation.
+			return
+		}
+
+		children := make(map[string][]string, len(sorted))
+		var roots []string
+		for _, s := range sorted {
+			if len(s.Parents) == 0 {
+				roots = append(roots, s.ID)
+			}
+			for _, parent := range s.Parents {
+				children[parent] = append(children[parent], s.ID)
+			}
+		}
+
+		inputs := make(map[string]chan Tour[T], len(sorted))
+		for _, s := range sorted {
+			inputs[s.ID] = make(chan Tour[T], ChannelCache)
+		}
+
+		quit := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, s := range sorted {
+			s := s
+			childChans := make([]chan<- Tour[T], 0, len(children[s.ID]))
+			for _, c := range children[s.ID] {
+				childChans = append(childChans, inputs[c])
+			}
+			isLeaf := len(children[s.ID]) == 0
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.runStage(ctx, quit, s, inputs[s.ID], childChans, isLeaf)
+			}()
+		}
+
+		stop := func() {
+			close(quit)
+			wg.Wait()
+		}
+
+		p.emitRe(RoutineEvent{typ: ReChannel, cid: id, status: RsReady})
+
+		for {
+			select {
+			case <-ctx.Done():
+				stop()
+				return
+			case v, ok := <-queue:
+				if !ok {
+					stop()
+					return
+				}
+				tr := Tour[T]{t: &v, visited: map[string]struct{}{}}
+				if p.conf.channelTimeout > 0 {
+					tr.deadline = time.Now().Add(p.conf.channelTimeout)
+				}
+				for _, r := range roots {
+					// Each root gets its own independent run through the
+					// DAG and finalizes (succeeds, errors or collides) on
+					// its own, so it gets its own in-flight slot.
+					atomic.AddInt32(&p.inFlight, 1)
+					inputs[r] <- cloneTour(tr)
+				}
+			}
+		}
+	}(id)
+}
+
+// joinEntry accumulates the arrivals a multi-parent stage has seen so far
+// for one originating Tour, identified by the pointer identity of its
+// underlying value (every clone along every branch shares the same *T).
+type joinEntry[T any] struct {
+	v       Tour[T]
+	arrived map[string]struct{} // parent stage IDs seen so far
+}
+
+// joinArrival folds one parent's arrival of v into the barrier for stage
+// and reports whether every parent has now arrived. Each branch's Tour
+// carries its own visited set (it only passed through one fan-in path), so
+// arrivals are attributed to a parent by checking which of stage.Parents
+// is already in v.visited, and the visited sets of every arrival are
+// merged so a later stage reachable from more than one branch sees every
+// stage this Tour actually passed through.
+func joinArrival[T any](joins map[*T]*joinEntry[T], stage Stage[T], v Tour[T]) (merged Tour[T], ready bool) {
+	key := v.t
+	entry, ok := joins[key]
+	if !ok {
+		entry = &joinEntry[T]{v: v, arrived: make(map[string]struct{}, len(stage.Parents))}
+		joins[key] = entry
+	} else {
+		for id := range v.visited {
+			entry.v.visited[id] = struct{}{}
+		}
+	}
+	for _, parent := range stage.Parents {
+		if _, arrived := v.visited[parent]; arrived {
+			entry.arrived[parent] = struct{}{}
+		}
+	}
+	if len(entry.arrived) < len(stage.Parents) {
+		return Tour[T]{}, false
+	}
+	delete(joins, key)
+	return entry.v, true
+}
+
+// runStage is the goroutine body for a single DAG stage: it reads Tours
+// from input, joins them once every parent of a multi-parent stage has
+// arrived, rejects ones that have already visited this stage with
+// ErrCollision, runs Fn under the configured timeout, and either fans the
+// result out to children or — for a leaf stage — reports it on the Park's
+// post-processing queue.
+func (p *Park[T]) runStage(ctx context.Context, quit <-chan struct{}, stage Stage[T], input <-chan Tour[T], children []chan<- Tour[T], isLeaf bool) {
+	needsJoin := len(stage.Parents) > 1
+	joins := make(map[*T]*joinEntry[T])
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.drainAbandon(input)
+			return
+		case <-quit:
+			p.drainAbandon(input)
+			return
+		case v := <-input:
+			if !p.waitWhilePaused(ctx) {
+				p.abandon()
+				p.drainAbandon(input)
+				return
+			}
+			if needsJoin {
+				merged, ready := joinArrival(joins, stage, v)
+				if !ready {
+					// This arrival is absorbed into the barrier rather than
+					// continuing on its own; its in-flight slot retires
+					// here instead of at a terminal event. The arrival that
+					// completes the barrier keeps its own slot, which
+					// becomes the merged entry's single continuing slot.
+					atomic.AddInt32(&p.inFlight, -1)
+					continue
+				}
+				v = merged
+			}
+			if _, seen := v.visited[stage.ID]; seen {
+				e := ErrCollision
+				v.err = &e
+				p.emit(&TourEvent[T]{Tour: &v, Reason: ReasonError, Err: ErrCollision})
+				p.finalize(v)
+				continue
+			}
+			v.visited[stage.ID] = struct{}{}
+
+			bound := p.conf.stageTimeout
+			if !v.deadline.IsZero() {
+				if remaining := time.Until(v.deadline); remaining < bound {
+					bound = remaining
+				}
+			}
+
+			done := make(chan error, 1)
+			go func(v Tour[T]) {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- errors.Errorf("stage panicked: %v", r)
+					}
+				}()
+				done <- stage.Fn(*v.t)
+			}(v)
+
+			var stageErr error
+			select {
+			case stageErr = <-done:
+			case <-time.After(bound):
+				stageErr = ErrTimeout
+			case <-ctx.Done():
+				p.abandon()
+				p.drainAbandon(input)
+				return
+			}
+
+			if stageErr != nil {
+				e := stageErr
+				v.err = &e
+				reason := ReasonError
+				if stageErr == ErrTimeout {
+					reason = ReasonTimeout
+				}
+				p.emit(&TourEvent[T]{Tour: &v, Reason: reason, Err: stageErr})
+				p.finalize(v)
+				continue
+			}
+
+			if isLeaf {
+				atomic.AddInt32(&p.succeeds, 1)
+				p.emit(&TourEvent[T]{Tour: &v, Reason: ReasonDone})
+				p.finalize(v)
+				continue
+			}
+			// v's slot continues as 1 of these children; fanning out to
+			// more than one splits it into that many independent slots.
+			if extra := len(children) - 1; extra > 0 {
+				atomic.AddInt32(&p.inFlight, int32(extra))
+			}
+			for _, c := range children {
+				c <- cloneTour(v)
+			}
+		}
+	}
+}
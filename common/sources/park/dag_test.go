@@ -0,0 +1,87 @@
+package park_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sharework-cn/smtools/common/sources/park"
+)
+
+// TestDiamondShapeReachesSink builds root -> {left, right} -> sink and
+// checks a single input value produces exactly one Tour on the post
+// processing queue, having passed through every stage.
+func TestDiamondShapeReachesSink(t *testing.T) {
+	var rootHits, leftHits, rightHits, sinkHits int32
+
+	stages := []park.Stage[int]{
+		{ID: "root", Fn: func(v int) error { atomic.AddInt32(&rootHits, 1); return nil }},
+		{ID: "left", Fn: func(v int) error { atomic.AddInt32(&leftHits, 1); return nil }, Parents: []string{"root"}},
+		{ID: "right", Fn: func(v int) error { atomic.AddInt32(&rightHits, 1); return nil }, Parents: []string{"root"}},
+		{ID: "sink", Fn: func(v int) error { atomic.AddInt32(&sinkHits, 1); return nil }, Parents: []string{"left", "right"}},
+	}
+
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithStages[int](stages),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+
+	data := make(chan int, 1)
+	data <- 42
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Start(ctx, data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	select {
+	case <-p.PostProcessed():
+	case <-time.After(800 * time.Millisecond):
+		t.Fatal("no tour reached the sink before the deadline")
+	}
+
+	if rootHits != 1 || leftHits != 1 || rightHits != 1 || sinkHits != 1 {
+		t.Fatalf("expected every stage to run exactly once, got root=%d left=%d right=%d sink=%d",
+			rootHits, leftHits, rightHits, sinkHits)
+	}
+}
+
+func TestIsSafeRejectsSelfReference(t *testing.T) {
+	stages := []park.Stage[int]{
+		{ID: "a", Fn: func(int) error { return nil }, Parents: []string{"a"}},
+	}
+	if err := park.IsSafe(stages); err == nil {
+		t.Fatal("expected an error for a stage that lists itself as its own parent")
+	}
+}
+
+func TestIsSafeRejectsCycle(t *testing.T) {
+	stages := []park.Stage[int]{
+		{ID: "a", Fn: func(int) error { return nil }, Parents: []string{"b"}},
+		{ID: "b", Fn: func(int) error { return nil }, Parents: []string{"a"}},
+	}
+	if err := park.IsSafe(stages); err == nil {
+		t.Fatal("expected an error for a two-stage cycle")
+	}
+}
+
+func TestWithStagesRejectsCycleAtConfigTime(t *testing.T) {
+	stages := []park.Stage[int]{
+		{ID: "a", Fn: func(int) error { return nil }, Parents: []string{"b"}},
+		{ID: "b", Fn: func(int) error { return nil }, Parents: []string{"a"}},
+	}
+	_, err := park.NewParkConf[int](park.WithStages[int](stages))
+	if err == nil {
+		t.Fatal("expected NewParkConf to reject a cyclic stage graph")
+	}
+}
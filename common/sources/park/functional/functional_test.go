@@ -0,0 +1,267 @@
+// Package functional spins up Parks wired to a ctlsrv.Server, injects
+// faults through the same control API an operator would use, and asserts
+// the Park recovers — modeled on etcd's functional tester.
+//
+// Tour's underlying value is deliberately unexported, so one Park's output
+// can't be re-threaded as another's input through the public API; "N parks
+// connected by a shared channel" is exercised here as N independent Park
+// instances all draining the same upstream data channel, the way a set of
+// worker processes share one work queue — the shape that's actually
+// reachable from outside the package.
+package functional_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sharework-cn/smtools/common/sources/park"
+	"github.com/sharework-cn/smtools/common/sources/park/ctlsrv"
+)
+
+// TestRecoversFromAPanickingStage injects a stage that panics on exactly
+// one Tour and checks later Tours still complete instead of the channel
+// goroutine dying with them.
+func TestRecoversFromAPanickingStage(t *testing.T) {
+	var calls int32
+	funcs := []func(int) error{
+		func(v int) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				panic("injected fault")
+			}
+			return nil
+		},
+	}
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int](funcs),
+		park.WithStageTimeout[int](200*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := p.Start(ctx, data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	data <- 1 // this Tour triggers the panic
+	data <- 2 // this one must still make it through
+
+	seenOK := false
+	deadline := time.After(time.Second)
+	for !seenOK {
+		select {
+		case tr := <-p.PostProcessed():
+			_ = tr
+			if atomic.LoadInt32(&calls) >= 2 {
+				seenOK = true
+			}
+		case <-deadline:
+			t.Fatal("channel did not recover from the panicking stage")
+		}
+	}
+}
+
+// TestStuckStageFailsWithoutWedgingHealthz starts a Park with a stage that
+// never returns, injects it via a real value, and checks /healthz keeps
+// reporting healthy (the event loop isn't wedged) even while that Tour is
+// timing out.
+func TestStuckStageFailsWithoutWedgingHealthz(t *testing.T) {
+	funcs := []func(int) error{
+		func(v int) error {
+			select {} // stuck forever
+		},
+	}
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int](funcs),
+		park.WithStageTimeout[int](50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := p.Start(ctx, data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	data <- 1
+
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, 500*time.Millisecond, 0).Handler())
+	defer srv.Close()
+
+	time.Sleep(100 * time.Millisecond) // let the stage hang past its timeout
+
+	resp, err := http.Get(fmt.Sprintf("%s/healthz", srv.URL))
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected /healthz to stay healthy despite a stuck stage, got %d", resp.StatusCode)
+	}
+}
+
+// newParkOn wires a Park[int] with funcs to data, starting it under ctx.
+func newParkOn(t *testing.T, ctx context.Context, data <-chan int, funcs []func(int) error) *park.Park[int] {
+	t.Helper()
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int](funcs),
+		park.WithStageTimeout[int](200*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	if err := p.Start(ctx, data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return p
+}
+
+// TestMultipleParksShareADataQueueSurviveAFault spins up 3 Parks draining
+// one shared upstream channel, modeling a pool of workers pulling off a
+// single queue. Every park's stage panics on whichever Tours it happens to
+// draw that trip the fault, recovering (per the existing per-stage panic
+// handling) by finalizing that Tour with a ReasonError itself — there's no
+// cross-park handoff of a faulted Tour to a different park. What the pool
+// must do is keep every member's channel goroutine alive and draining
+// through repeated panics, so the shared queue still empties completely
+// instead of one worker's fault wedging its share of it forever.
+//
+// This only checks total completions, not a per-Tour success/error split:
+// emit() is a deliberately best-effort, non-blocking send to the event
+// queue (see Park.emit), so a TourEvent listener can under-count panics
+// that raced a backed-up queue — that's existing, intentional behavior,
+// not something this test should fight.
+func TestMultipleParksShareADataQueueSurviveAFault(t *testing.T) {
+	const nParks = 3
+	const nTours = 30
+
+	data := make(chan int, nTours)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	parks := make([]*park.Park[int], nParks)
+	for i := range parks {
+		funcs := []func(int) error{func(v int) error {
+			if v%7 == 0 {
+				panic("injected fault")
+			}
+			return nil
+		}}
+		parks[i] = newParkOn(t, ctx, data, funcs)
+	}
+
+	for i := 0; i < nTours; i++ {
+		data <- i
+	}
+
+	// Fan every park's PostProcessed() into one channel so the drain loop
+	// below doesn't hardcode nParks into a fixed set of select cases.
+	done := make(chan struct{}, nTours)
+	for _, p := range parks {
+		p := p
+		go func() {
+			for range p.PostProcessed() {
+				done <- struct{}{}
+			}
+		}()
+	}
+
+	seen := 0
+	deadline := time.After(2 * time.Second)
+	for seen < nTours {
+		select {
+		case <-done:
+			seen++
+		case <-deadline:
+			t.Fatalf("only %d of %d tours completed; pool did not survive the panics", seen, nTours)
+		}
+	}
+}
+
+// TestControlAPIFaultInjectionPauseResume pauses a Park through ctlsrv's
+// POST /pause, the same endpoint an operator would hit, and checks Tours
+// queue up rather than complete while paused; POST /resume must then let
+// them drain, demonstrating recovery from the injected fault via the
+// control API itself rather than by calling Park methods directly.
+func TestControlAPIFaultInjectionPauseResume(t *testing.T) {
+	data := make(chan int, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	p := newParkOn(t, ctx, data, []func(int) error{func(int) error { return nil }})
+
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, time.Second, 0).Handler())
+	defer srv.Close()
+
+	if resp, err := http.Post(srv.URL+"/pause", "", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /pause: resp=%v err=%v", resp, err)
+	}
+
+	data <- 1
+	select {
+	case <-p.PostProcessed():
+		t.Fatal("tour completed while the Park was paused via the control API")
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	if resp, err := http.Post(srv.URL+"/resume", "", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /resume: resp=%v err=%v", resp, err)
+	}
+
+	select {
+	case <-p.PostProcessed():
+	case <-time.After(time.Second):
+		t.Fatal("tour never completed after /resume recovered the Park")
+	}
+}
+
+// TestClosedDataChannelStopsChannelCleanly checks that closing the upstream
+// data channel — the fault of the data source disappearing out from under a
+// channel — makes that channel goroutine exit cleanly rather than panic or
+// wedge the event loop, leaving /healthz reporting healthy throughout.
+func TestClosedDataChannelStopsChannelCleanly(t *testing.T) {
+	data := make(chan int, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	p := newParkOn(t, ctx, data, []func(int) error{func(int) error { return nil }})
+
+	srv := httptest.NewServer(ctlsrv.NewServer[int](p, time.Second, 0).Handler())
+	defer srv.Close()
+
+	data <- 1
+	<-p.PostProcessed()
+
+	close(data)
+	time.Sleep(100 * time.Millisecond) // let the channel goroutine observe the close
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected /healthz to stay healthy after the data channel closed, got %d", resp.StatusCode)
+	}
+}
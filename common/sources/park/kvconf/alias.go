@@ -0,0 +1,128 @@
+package kvconf
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sharework-cn/smtools/common/sources/park"
+)
+
+// aliasKey returns the path whose value names the active config prefix for
+// the Park named name.
+func aliasKey(name string) string {
+	return fmt.Sprintf("/park/%s/alias", name)
+}
+
+// Decoder turns the raw bytes stored at a published config prefix into a
+// park.Conf. Callers supply one matching however they serialize configs
+// (JSON, TOML, ...).
+type Decoder[T any] func(data []byte) (*park.Conf[T], error)
+
+// Loader resolves a named Park's configuration through the alias-key
+// indirection.
+type Loader[T any] struct {
+	kv     KV
+	name   string
+	decode Decoder[T]
+
+	mu         sync.Mutex
+	lastPrefix string // prefix most recently resolved by Load, used by Watch to skip no-op reconciles
+}
+
+// NewLoader builds a Loader for the Park named name, backed by kv.
+func NewLoader[T any](kv KV, name string, decode Decoder[T]) *Loader[T] {
+	return &Loader[T]{kv: kv, name: name, decode: decode}
+}
+
+// resolvePrefix reads the alias once and returns the prefix it currently
+// names; the prefix itself is never mutated after being published, so the
+// caller is guaranteed a complete, consistent config underneath it.
+func (l *Loader[T]) resolvePrefix(ctx context.Context) (string, error) {
+	key := aliasKey(l.name)
+	alias, err := l.kv.Get(ctx, key)
+	if err != nil {
+		return "", errors.Wrapf(err, "read alias %q", key)
+	}
+	if len(alias) == 0 {
+		return "", errors.Errorf("alias %q is not set", key)
+	}
+	return string(alias), nil
+}
+
+// Load resolves the active prefix and decodes the Conf published under it.
+func (l *Loader[T]) Load(ctx context.Context) (*park.Conf[T], error) {
+	prefix, err := l.resolvePrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	confKey := prefix + "/conf"
+	data, err := l.kv.Get(ctx, confKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q", confKey)
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("no config published at %q", confKey)
+	}
+	conf, err := l.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.lastPrefix = prefix
+	l.mu.Unlock()
+	return conf, nil
+}
+
+// Watch calls p.Reload every time the alias is flipped to a new prefix,
+// until stopCh is closed. A prefix that fails to load or decode is skipped
+// so a bad publish can't crash a running Park; the previous generation
+// keeps running.
+//
+// It subscribes before doing anything else, then immediately reconciles
+// against whatever prefix the alias currently names. Without that initial
+// reconcile, a flip that happens between Load (in the caller, before Watch
+// is even started) and the subscription taking effect would never be
+// observed: Watch would sit waiting for the next flip that may never come.
+// The reconcile only calls p.Reload when the resolved prefix actually
+// differs from the last one applied, so the initial catch-up (and any
+// duplicate alias events) can't force a needless channel close/reopen
+// cycle when nothing changed.
+func (l *Loader[T]) Watch(ctx context.Context, p *park.Park[T], stopCh <-chan struct{}) {
+	events := l.kv.Watch(aliasKey(l.name), stopCh)
+	l.mu.Lock()
+	lastPrefix := l.lastPrefix
+	l.mu.Unlock()
+	reconcile := func() {
+		prefix, err := l.resolvePrefix(ctx)
+		if err != nil || prefix == lastPrefix {
+			return
+		}
+		conf, err := l.Load(ctx)
+		if err != nil {
+			return
+		}
+		if err := p.Reload(conf); err != nil {
+			return
+		}
+		lastPrefix = prefix
+	}
+
+	reconcile()
+	for range events {
+		reconcile()
+	}
+}
+
+// Publish writes conf under a fresh prefix and then atomically (from a
+// reader's point of view) flips the alias to point at it.
+func Publish(ctx context.Context, kv KV, name, prefix string, conf []byte) error {
+	if err := kv.Put(ctx, prefix+"/conf", conf); err != nil {
+		return errors.Wrapf(err, "write %q", prefix+"/conf")
+	}
+	if err := kv.Put(ctx, aliasKey(name), []byte(prefix)); err != nil {
+		return errors.Wrapf(err, "flip alias %q", aliasKey(name))
+	}
+	return nil
+}
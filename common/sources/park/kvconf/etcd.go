@@ -0,0 +1,63 @@
+//go:build etcd
+
+package kvconf
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdKV is a KV backed by a real etcd v3 cluster. It is only compiled in
+// when building with -tags etcd, so consumers who don't need etcd aren't
+// forced to pull its client as a transitive dependency.
+type EtcdKV struct {
+	client *clientv3.Client
+}
+
+// NewEtcdKV wraps an already-connected etcd client.
+func NewEtcdKV(client *clientv3.Client) *EtcdKV {
+	return &EtcdKV{client: client}
+}
+
+func (e *EtcdKV) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := e.client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func (e *EtcdKV) Put(ctx context.Context, key string, value []byte) error {
+	_, err := e.client.Put(ctx, key, string(value))
+	return err
+}
+
+func (e *EtcdKV) Watch(prefix string, stopCh <-chan struct{}) <-chan Event {
+	out := make(chan Event, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	wch := e.client.Watch(ctx, prefix, clientv3.WithPrefix())
+
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	go func() {
+		defer close(out)
+		for resp := range wch {
+			for _, ev := range resp.Events {
+				typ := EventPut
+				if ev.Type == clientv3.EventTypeDelete {
+					typ = EventDelete
+				}
+				out <- Event{Type: typ, Key: string(ev.Kv.Key), Value: ev.Kv.Value}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,38 @@
+// Package kvconf lets a park.Park be configured, and re-configured while
+// running, from a KV store such as etcd, Consul or ZooKeeper.
+//
+// Reconfiguration uses the "alias key" pattern: the real configuration is
+// written under a fresh, immutable prefix (e.g. /park/<name>/v17) and only
+// becomes active once /park/<name>/alias is flipped to point at it. A
+// reader that resolves the alias then reads the prefix it named never
+// observes a half-written config, because the prefix it read from is never
+// mutated after being published.
+package kvconf
+
+import "context"
+
+// EventType classifies a KV change notification.
+type EventType int
+
+const (
+	EventPut EventType = iota + 1
+	EventDelete
+)
+
+// Event is a single change observed by a Watch.
+type Event struct {
+	Type  EventType
+	Key   string
+	Value []byte
+}
+
+// KV is the minimal key-value store a Loader needs. Get returns (nil, nil)
+// for a missing key, mirroring the common KV backend convention of not
+// treating "not found" as an error.
+type KV interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte) error
+	// Watch streams Events for keys under prefix until stopCh is closed, at
+	// which point the returned channel is closed.
+	Watch(prefix string, stopCh <-chan struct{}) <-chan Event
+}
@@ -0,0 +1,105 @@
+package kvconf_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sharework-cn/smtools/common/sources/park"
+	"github.com/sharework-cn/smtools/common/sources/park/kvconf"
+)
+
+func decode(data []byte) (*park.Conf[int], error) {
+	n := 0
+	if _, err := fmt.Sscanf(string(data), "%d", &n); err != nil {
+		return nil, err
+	}
+	return park.NewParkConf[int](park.WithNumChannels[int](n))
+}
+
+func TestLoaderNeverObservesAHalfWrittenGeneration(t *testing.T) {
+	ctx := context.Background()
+	kv := kvconf.NewMemKV()
+	loader := kvconf.NewLoader[int](kv, "test", decode)
+
+	if err := kvconf.Publish(ctx, kv, "test", "/park/test/v1", []byte("1")); err != nil {
+		t.Fatalf("publish v1: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	events := kv.Watch("/park/test/alias", stopCh)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := kvconf.Publish(ctx, kv, "test", "/park/test/v2", []byte("2")); err != nil {
+			t.Errorf("publish v2: %v", err)
+		}
+	}()
+
+	// Regardless of how the publish and the watcher interleave, every Load
+	// must resolve to a fully-formed generation (numChans 1 or 2), never a
+	// torn read.
+	for i := 0; i < 50; i++ {
+		conf, err := loader.Load(ctx)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if conf.NumChannels() != 1 && conf.NumChannels() != 2 {
+			t.Fatalf("torn read: got numChans=%d", conf.NumChannels())
+		}
+	}
+
+	<-events
+	<-done
+
+	conf, err := loader.Load(ctx)
+	if err != nil {
+		t.Fatalf("final load: %v", err)
+	}
+	if conf.NumChannels() != 2 {
+		t.Fatalf("expected the final generation to be v2 (numChans=2), got %d", conf.NumChannels())
+	}
+}
+
+func TestLoaderWatchReloadsPark(t *testing.T) {
+	ctx := context.Background()
+	kv := kvconf.NewMemKV()
+	loader := kvconf.NewLoader[int](kv, "test", decode)
+
+	if err := kvconf.Publish(ctx, kv, "test", "/park/test/v1", []byte("1")); err != nil {
+		t.Fatalf("publish v1: %v", err)
+	}
+
+	p := park.New[int]()
+	conf, err := loader.Load(ctx)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int)
+	if err := p.Start(context.Background(), data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go loader.Watch(ctx, p, stopCh)
+
+	if err := kvconf.Publish(ctx, kv, "test", "/park/test/v2", []byte("3")); err != nil {
+		t.Fatalf("publish v2: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if p.GetConf().NumChannels() == 3 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Park was not reloaded to the v2 generation in time")
+}
@@ -0,0 +1,85 @@
+package kvconf
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// MemKV is an in-memory KV for tests; it is safe for concurrent use.
+type MemKV struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	subs map[string][]chan Event
+}
+
+// NewMemKV returns an empty MemKV.
+func NewMemKV() *MemKV {
+	return &MemKV{
+		data: make(map[string][]byte),
+		subs: make(map[string][]chan Event),
+	}
+}
+
+func (m *MemKV) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	if !ok {
+		return nil, nil
+	}
+	cp := make([]byte, len(v))
+	copy(cp, v)
+	return cp, nil
+}
+
+func (m *MemKV) Put(ctx context.Context, key string, value []byte) error {
+	cp := make([]byte, len(value))
+	copy(cp, value)
+
+	m.mu.Lock()
+	// The sends below stay under m.mu so a Watch being stopped can't close
+	// a channel between collecting it here and sending to it.
+	defer m.mu.Unlock()
+	m.data[key] = cp
+	var notify []chan Event
+	for prefix, subs := range m.subs {
+		if strings.HasPrefix(key, prefix) {
+			notify = append(notify, subs...)
+		}
+	}
+
+	e := Event{Type: EventPut, Key: key, Value: cp}
+	for _, ch := range notify {
+		select {
+		case ch <- e:
+		default:
+			// a slow watcher misses an intermediate event rather than blocking Put.
+		}
+	}
+	return nil
+}
+
+func (m *MemKV) Watch(prefix string, stopCh <-chan struct{}) <-chan Event {
+	ch := make(chan Event, 8)
+
+	m.mu.Lock()
+	m.subs[prefix] = append(m.subs[prefix], ch)
+	m.mu.Unlock()
+
+	go func() {
+		<-stopCh
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		subs := m.subs[prefix]
+		for i, c := range subs {
+			if c == ch {
+				m.subs[prefix] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
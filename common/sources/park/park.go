@@ -1,13 +1,13 @@
 package park
 
 import (
+	"context"
 	stderrors "errors"
-	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
-	"log"
+
 	"github.com/pkg/errors"
-	"google.golang.org/genproto/googleapis/cloud/dataproc/v1"
 )
 
 const (
@@ -15,26 +15,50 @@ const (
 	MaxFunctions = 64
 	MaxListeners = 256
 	ChannelCache = 8
+
+	// DefaultStageTimeout bounds a single stage function call when no
+	// WithStageTimeout option is given.
+	DefaultStageTimeout = 5 * time.Minute
+	// DefaultReadTimeout is how long a channel waits for the upstream data
+	// queue to produce something before it drains gracefully.
+	DefaultReadTimeout = 0 // disabled by default
 )
 
 var (
-	ErrArg         = stderrors.New("invalid argument")
-	ErrState       = stderrors.New("invalid state")
-//	ErrOp    = stderrors.New("invalid operation")
+	ErrArg   = stderrors.New("invalid argument")
+	ErrState = stderrors.New("invalid state")
 	ErrAbort = stderrors.New("abort anyway")
+	// ErrTimeout is recorded on a Tour whose stage function did not return
+	// within its configured timeout.
+	ErrTimeout = stderrors.New("stage timed out")
+)
+
+// Reason classifies why a TourEvent was emitted.
+type Reason int
+
+const (
+	ReasonProgress Reason = iota + 1
+	ReasonDone
+	ReasonError
+	ReasonTimeout
 )
 
 // TourEvent passes to the listeners on the status change of Tours
-type TourEvent[T any] struct{}
+type TourEvent[T any] struct {
+	Tour   *Tour[T]
+	Stage  int
+	Reason Reason
+	Err    error
+}
 
 // RoutineStatus indicates the status of a go routine
 type RoutineStatus int
 
 const (
-	RsInit RoutineStatus = iota + 1	// Go routine created
-	RsReady	// Routine is ready for work
-	RsClosing	// Routine is about to close, it's read only
-	RsClosed	// Routine is closed
+	RsInit    RoutineStatus = iota + 1 // Go routine created
+	RsReady                            // Routine is ready for work
+	RsClosing                          // Routine is about to close, it's read only
+	RsClosed                           // Routine is closed
 )
 
 type ReType int
@@ -45,30 +69,49 @@ const (
 )
 
 // RoutineEvent notifies the status of channel and their functions
-type RoutineEvent struct{
-	typ 	ReType
-	cid int
-	fid int
+type RoutineEvent struct {
+	typ    ReType
+	cid    int
+	fid    int
 	status RoutineStatus
 }
 
-// GetConf Park Configurations
+// Type reports whether the event concerns a channel or a stage function.
+func (e RoutineEvent) Type() ReType { return e.typ }
+
+// ChannelID is the channel this event concerns.
+func (e RoutineEvent) ChannelID() int { return e.cid }
+
+// FuncID is the stage function this event concerns, when Type is ReFunc.
+func (e RoutineEvent) FuncID() int { return e.fid }
+
+// Status is the routine's new status.
+func (e RoutineEvent) Status() RoutineStatus { return e.status }
+
+// Conf Park Configurations
 type Conf[T any] struct {
-	numChans  int                // specify the number of channels
-	funcs     []func(T) error   // functions to be called in each channel
-	listeners []func(*TourEvent[T]) // listeners who concerns with tour status change
+	numChans  int                   // specify the number of channels
+	funcs     []func(T) error       // functions to be called in each channel, as a linear chain
+	stages    []Stage[T]            // functions to be called in each channel, as a DAG; set by WithStages instead of WithFuncs
+	listeners []func(*TourEvent[T]) // listeners who concern with tour status change
+
+	stageTimeout   time.Duration // upper bound for a single stage function call
+	channelTimeout time.Duration // upper bound for a tour to cross an entire channel
+	readTimeout    time.Duration // how long a channel tolerates an idle upstream
 }
 
 // Optf An option function defines the way to set a configuration option
 type Optf[T any] func(*Conf[T]) error
 
-// NewParkConf Create a new `GetConf`
+// NewParkConf Create a new `Conf`
 func NewParkConf[T any](optfs ...Optf[T]) (*Conf[T], error) {
 	// build a default options
 	conf := &Conf[T]{
-		numChans:  1,
-		funcs:     make([]func(T) error, 4),
-		listeners: make([]func(*TourEvent[T]), 2),
+		numChans:     1,
+		funcs:        nil,
+		listeners:    nil,
+		stageTimeout: DefaultStageTimeout,
+		readTimeout:  DefaultReadTimeout,
 	}
 	// accept custom options
 	for _, optf := range optfs {
@@ -92,12 +135,12 @@ func WithNumChannels[T any](v int) Optf[T] {
 }
 
 // WithFuncs return a function to set functions
-func WithFuncs[T any](t []func(T) error) Optf[T] {
+func WithFuncs[T any](fs []func(T) error) Optf[T] {
 	return func(conf *Conf[T]) error {
-		if len(t) > MaxFunctions {
+		if len(fs) > MaxFunctions {
 			return ErrArg
 		}
-		conf.funcs = t
+		conf.funcs = fs
 		return nil
 	}
 }
@@ -113,20 +156,89 @@ func WithListeners[T any](ls []func(*TourEvent[T])) Optf[T] {
 	}
 }
 
+// WithStageTimeout bounds how long a single stage function may run before
+// its Tour is failed with ErrTimeout and handed to the post-processing queue.
+func WithStageTimeout[T any](d time.Duration) Optf[T] {
+	return func(conf *Conf[T]) error {
+		if d <= 0 {
+			return ErrArg
+		}
+		conf.stageTimeout = d
+		return nil
+	}
+}
+
+// WithChannelTimeout bounds how long a Tour may take to cross an entire
+// channel, from the first stage to the last.
+func WithChannelTimeout[T any](d time.Duration) Optf[T] {
+	return func(conf *Conf[T]) error {
+		if d <= 0 {
+			return ErrArg
+		}
+		conf.channelTimeout = d
+		return nil
+	}
+}
+
+// WithReadTimeout sets how long a channel waits for the upstream data queue
+// to produce a value before draining gracefully instead of blocking forever.
+func WithReadTimeout[T any](d time.Duration) Optf[T] {
+	return func(conf *Conf[T]) error {
+		if d <= 0 {
+			return ErrArg
+		}
+		conf.readTimeout = d
+		return nil
+	}
+}
+
 // NumChannels get the number of channels
-func (opts Conf[T]) NumChannels() int {
-	return opts.numChans
+func (c Conf[T]) NumChannels() int {
+	return c.numChans
+}
+
+// StageTimeout gets the configured per-stage timeout
+func (c Conf[T]) StageTimeout() time.Duration {
+	return c.stageTimeout
+}
+
+// ChannelTimeout gets the configured per-channel timeout
+func (c Conf[T]) ChannelTimeout() time.Duration {
+	return c.channelTimeout
+}
+
+// ReadTimeout gets the configured idle-upstream timeout
+func (c Conf[T]) ReadTimeout() time.Duration {
+	return c.readTimeout
 }
 
 // Park The Park container
 type Park[T any] struct {
-	conf     *Conf[T]      // configurations
-	es 		*EventServer[T] // event server
-	dq 	<-chan T            // data queue which provided by client
-	dsq 	chan Tour[T]       // dispatching queue
-	ppq chan Tour[T]          // post processing queue
-	endq chan struct{}        // ending queue
-	status   Status           // status
+	conf *Conf[T]        // configurations
+	es   *EventServer[T] // event server
+
+	dq   <-chan T      // data queue which provided by client
+	ppq  chan Tour[T]  // post processing queue
+	endq chan struct{} // ending queue
+
+	cancel   func()          // cancels the context passed to Start
+	startCtx context.Context // the ctx Start was given, re-derived from on every Reload
+	status   Status          // status
+	succeeds int32           // count of tours that completed a channel successfully
+	paused   int32           // 1 while Pause()d, checked by the channel goroutines
+	inFlight int32           // count of Tours accepted into a channel but not yet on ppq
+
+	reListeners []func(RoutineEvent) // notified of channel open/close transitions
+
+	chanMu     sync.Mutex
+	chanStates map[int]ChannelState // per-channel RoutineStatus, keyed by channel id
+}
+
+// ChannelState is one channel's most recently reported RoutineStatus and
+// when it was entered, as tracked from the RoutineEvents Start/Reload emit.
+type ChannelState struct {
+	Status RoutineStatus
+	Since  time.Time
 }
 
 // Status of park
@@ -140,20 +252,40 @@ const (
 	ParkAborted                    // canceled
 )
 
-/*
-Event Server!
-*/
+// EventServer dispatches TourEvents to the configured listeners without
+// blocking the channel goroutines that produce them.
 type EventServer[T any] struct {
-	listeners []func(*TourEvent[T])	// listeners
-	eq chan TourEvent[T] // event queue
-	eqx chan struct{} // exiting queue for the looping on the eq
-	eeq chan struct{}	// ending queue for the eq
+	mu        sync.Mutex
+	listeners []func(*TourEvent[T]) // listeners
+	eq        chan *TourEvent[T]    // event queue
+	eeq       chan struct{}         // ending queue for the eq loop
+}
+
+func (es *EventServer[T]) addListener(l func(*TourEvent[T])) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.listeners = append(es.listeners, l)
+}
+
+func (es *EventServer[T]) snapshotListeners() []func(*TourEvent[T]) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return append([]func(*TourEvent[T]){}, es.listeners...)
 }
 
 // The Tour hold the information about the tourist
 type Tour[T any] struct {
 	err *error
 	t   *T
+
+	// deadline is when this Tour must have crossed the whole channel, per
+	// Conf.channelTimeout; the zero value means no channel-wide deadline.
+	deadline time.Time
+
+	// visited tracks the stage IDs this Tour has already been processed by,
+	// when running a DAG built with WithStages; it is nil for the legacy
+	// linear-chain mode built with WithFuncs.
+	visited map[string]struct{}
 }
 
 // a singleton instance of park, the generic type is any
@@ -165,10 +297,10 @@ func init() {
 
 // park creation method for multi instance mode
 func New[T any]() *Park[T] {
-	park := new(Park[T])
-	park.conf, _ = NewParkConf[T]()
-	park.Reset()
-	return park
+	p := new(Park[T])
+	p.conf, _ = NewParkConf[T]()
+	_ = p.Reset()
+	return p
 }
 
 // get the status of park
@@ -177,8 +309,8 @@ func GetStatus() Status {
 }
 
 // get the status of park
-func (t *Park[T]) Status() Status {
-	return t.status
+func (p *Park[T]) Status() Status {
+	return p.status
 }
 
 // get the configuration of park
@@ -187,22 +319,22 @@ func GetConf() *Conf[any] {
 }
 
 // get the configuration of park
-func (t *Park[T]) GetConf() *Conf[T] {
-	return t.conf
+func (p *Park[T]) GetConf() *Conf[T] {
+	return p.conf
 }
 
 // set the configuration of park
-func SetConf(options *GetConf[any]) error {
-	return t.SetConf(options)
+func SetConf(conf *Conf[any]) error {
+	return t.SetConf(conf)
 }
 
 // set the configuration of park
-func (t *Park[T]) SetConf(options *GetConf[T]) error {
-	if t.status != StateInitial {
-		return errors.WithMessagef(ErrState, 
-			"Can not configurate in state : %d", t.status)
+func (p *Park[T]) SetConf(conf *Conf[T]) error {
+	if p.status != StateInitial {
+		return errors.WithMessagef(ErrState,
+			"can not configure in state: %d", p.status)
 	}
-	t.conf = options
+	p.conf = conf
 	return nil
 }
 
@@ -212,131 +344,327 @@ func Cancel() error {
 }
 
 // cancel all ongoing tour
-func (t *Park[T]) Cancel() error {
-	t.status = ParkAborted
+func (p *Park[T]) Cancel() error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.status = ParkAborted
+	return nil
+}
+
+// Pause suspends delivery of in-flight Tours to their next stage until
+// Resume is called; channels stay up and keep their place in the pipeline.
+func (p *Park[T]) Pause() error {
+	if p.status != Open {
+		return errors.WithMessagef(ErrState, "can not pause in state: %d", p.status)
+	}
+	atomic.StoreInt32(&p.paused, 1)
+	p.status = ParkPaused
 	return nil
 }
 
+// Resume undoes a prior Pause.
+func (p *Park[T]) Resume() error {
+	if p.status != ParkPaused {
+		return errors.WithMessagef(ErrState, "can not resume in state: %d", p.status)
+	}
+	atomic.StoreInt32(&p.paused, 0)
+	p.status = Open
+	return nil
+}
+
+// waitWhilePaused blocks the calling channel goroutine while the Park is
+// paused. It reports false if ctx is done first, telling the caller to exit.
+func (p *Park[T]) waitWhilePaused(ctx context.Context) bool {
+	for atomic.LoadInt32(&p.paused) == 1 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return true
+}
+
+// AddListener registers a listener notified of every TourEvent, in addition
+// to any set on the Conf via WithListeners.
+func (p *Park[T]) AddListener(l func(*TourEvent[T])) {
+	if p.es == nil {
+		return
+	}
+	p.es.addListener(l)
+}
+
+// Heartbeat enqueues a liveness probe (a TourEvent with a nil Tour, tagged
+// with token in its Stage field) onto the event dispatch queue. It reports
+// whether the queue accepted the probe without blocking; callers such as
+// ctlsrv pair this with a listener that watches for the token coming back
+// out the other end to detect a wedged event loop.
+func (p *Park[T]) Heartbeat(token int) bool {
+	if p.es == nil {
+		return false
+	}
+	select {
+	case p.es.eq <- &TourEvent[T]{Stage: token, Reason: ReasonProgress}:
+		return true
+	default:
+		return false
+	}
+}
+
 // reset the runtime information of the park
 func Reset() error {
 	return t.Reset()
 }
 
 // reset the runtime information of the park
-func (t *Park[T]) Reset() error {
-	if t.status == ParkPaused || t.status == Open {
-		err := t.Cancel()
-		if err != nil {
+func (p *Park[T]) Reset() error {
+	if p.status == ParkPaused || p.status == Open {
+		if err := p.Cancel(); err != nil {
 			return errors.Wrap(err, "cancel")
 		}
-	} else {
-		if t.status == ParkClosed {
-			return errors.Wrap(ErrState, "Can not reset the park when it's closed")
-		}
+	} else if p.status == ParkClosed {
+		return errors.Wrap(ErrState, "can not reset the park when it's closed")
 	}
-	t.status = StateInitial
+	p.status = StateInitial
+	p.cancel = nil
 	return nil
 }
 
+// start running
+func Start(ctx context.Context, data <-chan any) (err error) {
+	return t.Start(ctx, data)
+}
 
+// Start begins pumping data through conf.numChans parallel channels, each
+// running conf.funcs in sequence, or the conf.stages DAG when WithStages
+// set one. Every stage invocation is bounded by
+// conf.stageTimeout: a stage function that overruns is abandoned and its
+// Tour is failed with ErrTimeout rather than stalling the pipeline.
+func (p *Park[T]) Start(ctx context.Context, data <-chan T) (err error) {
+	p.startCtx = ctx
+	ctx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.dq = data
+	p.ppq = make(chan Tour[T], ChannelCache)
+	p.endq = make(chan struct{})
+	p.newEventServer()
+	go p.es.start()
+
+	p.runChannels(ctx, data)
+	p.status = Open
+	return nil
+}
 
-// start running
-func Start(data <-chan any) (err error) {
-	return t.Start()
+// runChannels wraps data with the configured read timeout (if any) and
+// starts conf.numChans channels reading from it under ctx. Each channel is
+// reported RsInit here, before its goroutine is spawned, and reports its own
+// RsReady once its pipeline is actually wired up and it starts reading — not
+// here, since newChannel only launches the goroutine and returns, well
+// before that work is done — so ChannelStates (and ctlsrv's stuckInitTimeout
+// check) reflect a channel still setting up, not just the ones Reload tears
+// down and restarts.
+func (p *Park[T]) runChannels(ctx context.Context, data <-chan T) {
+	source := data
+	if p.conf.readTimeout > 0 {
+		source = NewTimeoutReader(ctx, data, p.conf.readTimeout)
+	}
+	for i := 0; i < p.conf.numChans; i++ {
+		p.emitRe(RoutineEvent{typ: ReChannel, cid: i, status: RsInit})
+		p.newChannel(ctx, i, source)
+	}
 }
 
-// start running
-func (t *Park[T]) Start(data <-chan any) (err error) {
-	t.dq = data
-	t.dsq = make(chan Tour[T], ChannelCache)
-	t.ppq = make(chan Tour[T], ChannelCache)
-	t.eq = make(chan TourEvent, ChannelCache)
-	t.endq = make(chan struct{})
-	t.eeq = make(chan struct{})
-	
-	chls := t.conf.numChans	
-	for i := 0; i < chls; i++ {
-		t.newChannel(i, data)
+// AddReListener registers a listener notified of RoutineEvent transitions,
+// e.g. channels closing and reopening across a Reload.
+func (p *Park[T]) AddReListener(l func(RoutineEvent)) {
+	p.reListeners = append(p.reListeners, l)
+}
+
+func (p *Park[T]) emitRe(e RoutineEvent) {
+	if e.typ == ReChannel {
+		p.chanMu.Lock()
+		if p.chanStates == nil {
+			p.chanStates = make(map[int]ChannelState)
+		}
+		p.chanStates[e.cid] = ChannelState{Status: e.status, Since: time.Now()}
+		p.chanMu.Unlock()
+	}
+	for _, l := range p.reListeners {
+		l(e)
 	}
-	return nil
 }
 
-func (t *Park[T]) startEventListener() {
-	t.dq = data
-	t.dsq = make(chan Tour[T], ChannelCache)
-	t.ppq = make(chan Tour[T], ChannelCache)
-	t.eq = make(chan TourEvent, ChannelCache)
-	t.endq = make(chan struct{})
-	t.eeq = make(chan struct{})
-	
-	chls := t.conf.numChans	
-	for i := 0; i < chls; i++ {
-		t.newChannel(i, data)
+// ChannelStates snapshots the most recently reported RoutineStatus of every
+// channel Start or Reload has touched, keyed by channel id.
+func (p *Park[T]) ChannelStates() map[int]ChannelState {
+	p.chanMu.Lock()
+	defer p.chanMu.Unlock()
+	states := make(map[int]ChannelState, len(p.chanStates))
+	for id, s := range p.chanStates {
+		states[id] = s
 	}
+	return states
+}
+
+// InFlight reports how many Tours have been accepted into a channel but
+// have not yet reached PostProcessed.
+func (p *Park[T]) InFlight() int32 {
+	return atomic.LoadInt32(&p.inFlight)
+}
+
+// Reload swaps in a new Conf while the Park is running: it signals the
+// current channels to close, waits out conf.channelTimeout (or a short
+// default) to let in-flight tours drain, then restarts conf.numChans
+// channels against the same upstream data queue under the new Conf. Every
+// channel's closing and reopening is reported via RoutineEvent.
+func (p *Park[T]) Reload(conf *Conf[T]) error {
+	if p.status != Open {
+		return errors.WithMessagef(ErrState, "can not reload in state: %d", p.status)
+	}
+	oldChans := p.conf.numChans
+	for i := 0; i < oldChans; i++ {
+		p.emitRe(RoutineEvent{typ: ReChannel, cid: i, status: RsClosing})
+	}
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	grace := conf.channelTimeout
+	if grace <= 0 {
+		grace = time.Second
+	}
+
+	// Swap in the new Conf before draining so GetConf() reflects it as soon
+	// as Reload is called, instead of only after the grace sleep below.
+	p.conf = conf
+	time.Sleep(grace)
+	for i := 0; i < oldChans; i++ {
+		p.emitRe(RoutineEvent{typ: ReChannel, cid: i, status: RsClosed})
+	}
+
+	ctx, cancel := context.WithCancel(p.startCtx)
+	p.cancel = cancel
+	p.runChannels(ctx, p.dq)
 	return nil
 }
 
-/*
-func (t *Park[T]) waitForChannelsReady() (err error) {
-	cnt := 0
-	stopLooping := false 
-	chls := t.conf.numChans
+// PostProcessed returns the queue of Tours that have either completed every
+// stage or failed (including by timeout).
+func (p *Park[T]) PostProcessed() <-chan Tour[T] {
+	return p.ppq
+}
+
+// finalize reports v on ppq and retires the in-flight slot it was given when
+// it entered the pipeline. Every Tour (and, for a DAG channel, every
+// per-root clone of one) passes through exactly one finalize call, whether
+// it succeeded, errored, timed out or collided, so InFlight stays balanced.
+func (p *Park[T]) finalize(v Tour[T]) {
+	atomic.AddInt32(&p.inFlight, -1)
+	p.ppq <- v
+}
+
+// abandon retires an in-flight slot for a Tour that is being dropped rather
+// than finalized, because its channel is shutting down (ctx cancelled by
+// Reload or the caller) mid-stage. It never reaches ppq: the channel tearing
+// it down is itself being discarded, so there's no result to report.
+func (p *Park[T]) abandon() {
+	atomic.AddInt32(&p.inFlight, -1)
+}
+
+// drainAbandon retires the in-flight slot of every Tour still buffered in
+// queue. Called only by the goroutine that's the sole reader of queue, as it
+// shuts down, so nothing else can be concurrently reading queue out from
+// under it: otherwise those buffered Tours would never be finalized or
+// abandoned, and InFlight would drift upward permanently across Reloads.
+func (p *Park[T]) drainAbandon(queue <-chan Tour[T]) {
 	for {
 		select {
-		case e, ok := <-t.rec:
-			if ok {
-				if e.typ == ReChannel && e.status == RsReady {
-					cnt++
-				}
-				if cnt >= chls {
-					stopLooping = true 
-				}
-			} else {
-				stopLooping = true 
-			}
-		}
-		if stopLooping {
-			break
+		case <-queue:
+			p.abandon()
+		default:
+			return
 		}
 	}
-	if cnt < chls {
-		return errors.WithMessagef(ErrOp, "Ready channels %d, desired %d", cnt, chls)
-	}
-	return nil
 }
-*/
 
 type uow[T any] struct {
-	queue chan *Tour[T]
-	quit  chan *Tour[T]
+	queue chan Tour[T]
+	quit  chan struct{}
 }
 
-func (p *Park[T]) start(f func( data chan<- T) err error,	next chan<- T, errc ->chan T) *uow[T] {
-	queue := make(chan *Tour[T], 10)
-	quit := make(chan *Tour[T])
-
-	l := len(*p.conf.funcs) - 1
+// start launches the goroutine for a single stage function f. Tours read
+// from queue are handed to f with a bound of timeout; an overrun marks the
+// Tour with ErrTimeout and reports it to errc instead of next.
+func (p *Park[T]) start(ctx context.Context, stage int, f func(T) error, timeout time.Duration, next chan<- Tour[T], errc chan<- Tour[T]) *uow[T] {
+	queue := make(chan Tour[T], ChannelCache)
+	quit := make(chan struct{})
 
 	go func() {
 		for {
 			select {
+			case <-ctx.Done():
+				p.drainAbandon(queue)
+				return
 			case <-quit:
+				p.drainAbandon(queue)
 				return
 			case v := <-queue:
-				err := f(v.t)
-				if err != nil {
-					v.err = &err
-					v -> errc
+				if !p.waitWhilePaused(ctx) {
+					p.abandon()
+					p.drainAbandon(queue)
+					return
+				}
+				done := make(chan error, 1)
+				go func(v Tour[T]) {
+					defer func() {
+						if r := recover(); r != nil {
+							done <- errors.Errorf("stage panicked: %v", r)
+						}
+					}()
+					done <- f(*v.t)
+				}(v)
+
+				bound := timeout
+				if !v.deadline.IsZero() {
+					if remaining := time.Until(v.deadline); remaining < bound {
+						bound = remaining
+					}
 				}
-				if id >= l {
-					atomic.AddInt32(&(p.succeeds), 1)
-					for _, listener := range *p.conf.listeners {
-						// TODO : compose the event when tour finished
-						listener(&TourEvent{})
+
+				select {
+				case err := <-done:
+					if err != nil {
+						e := err
+						v.err = &e
+						p.emit(&TourEvent[T]{Tour: &v, Stage: stage, Reason: ReasonError, Err: err})
+						if errc != nil {
+							p.finalize(v)
+						}
+						continue
 					}
+					p.emit(&TourEvent[T]{Tour: &v, Stage: stage, Reason: ReasonProgress})
+				case <-time.After(bound):
+					e := ErrTimeout
+					v.err = &e
+					p.emit(&TourEvent[T]{Tour: &v, Stage: stage, Reason: ReasonTimeout, Err: ErrTimeout})
+					if errc != nil {
+						p.finalize(v)
+					}
+					continue
+				case <-ctx.Done():
+					p.abandon()
+					p.drainAbandon(queue)
+					return
 				}
+
 				if next != nil {
 					next <- v
+				} else if errc != nil {
+					atomic.AddInt32(&p.succeeds, 1)
+					p.emit(&TourEvent[T]{Tour: &v, Stage: stage, Reason: ReasonDone})
+					p.finalize(v)
 				}
 			}
 		}
@@ -344,20 +672,35 @@ func (p *Park[T]) start(f func( data chan<- T) err error,	next chan<- T, errc ->
 	return &uow[T]{queue: queue, quit: quit}
 }
 
-func (p *Park[T]) newChannel(id int, queue <-chan *Tour[T]) {
+// newChannel builds the pipeline for this channel and feeds it from queue,
+// tagging every Tour it produces with a per-channel deadline derived from
+// conf.channelTimeout when one is configured. When conf.stages is set (via
+// WithStages) the pipeline is the DAG it describes; otherwise it's the
+// legacy linear chain of conf.funcs (via WithFuncs).
+func (p *Park[T]) newChannel(ctx context.Context, id int, queue <-chan T) {
+	if len(p.conf.stages) > 0 {
+		p.newDAGChannel(ctx, id, queue)
+		return
+	}
 	go func(id int) {
-		l := len(p.conf.numChans)
+		l := len(p.conf.funcs)
 		uows := make([]*uow[T], l)
 		for i := l - 1; i >= 0; i-- {
-			if i == l-1 {
-				uows[i] = p.start(id, (*p.options.channelFuncs)[i], nil)
-			} else {
-				uows[i] = p.start(id, (*p.options.channelFuncs)[i], (*uows[i+1]).queue)
+			var next chan Tour[T]
+			if i < l-1 {
+				next = uows[i+1].queue
 			}
+			uows[i] = p.start(ctx, i, p.conf.funcs[i], p.conf.stageTimeout, next, p.ppq)
 		}
+		p.emitRe(RoutineEvent{typ: ReChannel, cid: id, status: RsReady})
 
 		for {
 			select {
+			case <-ctx.Done():
+				for _, u := range uows {
+					close(u.quit)
+				}
+				return
 			case v, ok := <-queue:
 				if !ok {
 					for _, u := range uows {
@@ -365,57 +708,54 @@ func (p *Park[T]) newChannel(id int, queue <-chan *Tour[T]) {
 					}
 					return
 				}
+				tr := Tour[T]{t: &v}
+				if p.conf.channelTimeout > 0 {
+					tr.deadline = time.Now().Add(p.conf.channelTimeout)
+				}
+				atomic.AddInt32(&p.inFlight, 1)
 				if l > 0 {
-					(*uows[0]).queue <- v
+					uows[0].queue <- tr
+				} else {
+					p.finalize(tr)
 				}
 			}
 		}
 	}(id)
 }
 
-
+func (p *Park[T]) emit(e *TourEvent[T]) {
+	if p.es == nil {
+		return
+	}
+	select {
+	case p.es.eq <- e:
+	default:
+		// the event server is backed up; drop rather than block a worker.
+	}
+}
 
 // create a new event server
-func (t *Park[T])newEventServer() {
-	t.es = &EventServer[T]{
-		listeners: t.conf.listeners,
-		eq: make( chan TourEvent[T], ChannelCache),
-		eqx: make( chan struct{}),
-		eeq: make( chan struct{}),
-	}
-}
-
-func (es *EventServer[T])start() {
-	defer func() {
-		if err := recover(); err != nil && err != ErrAbort {
-			
-			const size = 64 << 10
-			buf := make([]byte, size)
-			buf = buf[:runtime.Stack(buf, false)]
-			c.server.logf("http: panic serving %v: %v\n%s", c.remoteAddr, err, buf)
-		}
-		if inFlightResponse != nil {
-			inFlightResponse.cancelCtx()
-		}
-		if !c.hijacked() {
-			if inFlightResponse != nil {
-				inFlightResponse.conn.r.abortPendingRead()
-				inFlightResponse.reqBody.Close()
-			}
-			c.close()
-			c.setState(c.rwc, StateClosed, runHooks)
-		}
-	}()
+func (p *Park[T]) newEventServer() {
+	p.es = &EventServer[T]{
+		listeners: p.conf.listeners,
+		eq:        make(chan *TourEvent[T], ChannelCache),
+		eeq:       make(chan struct{}),
+	}
+}
 
+func (es *EventServer[T]) start() {
 	for {
 		select {
-		case <-es.eqx:
-			break
-		case e, ok := <-es.eq:
-			for i, l := range es.listeners {
-				
-				l(*e)
+		case <-es.eeq:
+			return
+		case e := <-es.eq:
+			for _, l := range es.snapshotListeners() {
+				l(e)
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+func (es *EventServer[T]) stop() {
+	close(es.eeq)
+}
@@ -0,0 +1,215 @@
+package park_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sharework-cn/smtools/common/sources/park"
+)
+
+// TestSlowStageDoesNotStarveOtherChannels exercises a 3-stage, 2-channel
+// Park where stage 2 blocks forever on the value 0, whichever channel reads
+// it; both Tours must still reach PostProcessed, the stuck one by timing
+// out, rather than the stuck stage wedging the whole Park.
+func TestSlowStageDoesNotStarveOtherChannels(t *testing.T) {
+	funcs := []func(int) error{
+		func(v int) error { return nil },
+		func(v int) error {
+			if v == 0 {
+				select {} // simulate a stage that never returns
+			}
+			return nil
+		},
+		func(v int) error { return nil },
+	}
+
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](2),
+		park.WithFuncs[int](funcs),
+		park.WithStageTimeout[int](50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+
+	data := make(chan int, 2)
+	data <- 0
+	data <- 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Start(ctx, data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	seen := 0
+	deadline := time.After(800 * time.Millisecond)
+	for seen < 2 {
+		select {
+		case tr := <-p.PostProcessed():
+			_ = tr
+			seen++
+		case <-deadline:
+			t.Fatalf("only observed %d of 2 tours before the deadline; channel 1 was starved", seen)
+		}
+	}
+}
+
+func TestErrTimeoutIsDistinguishable(t *testing.T) {
+	if !errors.Is(park.ErrTimeout, park.ErrTimeout) {
+		t.Fatal("ErrTimeout should be comparable via errors.Is")
+	}
+}
+
+// TestChannelStatesReportRsReadyAfterStart checks that Start, not just
+// Reload, reports every channel's RoutineStatus, so a ctlsrv.Server backed
+// by a freshly-started Park doesn't have to wait for a Reload to see one.
+func TestChannelStatesReportRsReadyAfterStart(t *testing.T) {
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](2),
+		park.WithFuncs[int]([]func(int) error{func(int) error { return nil }}),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int)
+	if err := p.Start(context.Background(), data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	allReady := func() bool {
+		states := p.ChannelStates()
+		if len(states) != 2 {
+			return false
+		}
+		for _, cs := range states {
+			if cs.Status != park.RsReady {
+				return false
+			}
+		}
+		return true
+	}
+	deadline := time.Now().Add(time.Second)
+	for !allReady() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if states := p.ChannelStates(); !allReady() {
+		t.Fatalf("expected both channels to report RsReady shortly after Start, got %+v", states)
+	}
+}
+
+// TestInFlightTracksPendingTours checks that InFlight rises while a Tour is
+// inside a stage and falls back to 0 once it's been reported on
+// PostProcessed.
+func TestInFlightTracksPendingTours(t *testing.T) {
+	release := make(chan struct{})
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int]([]func(int) error{func(int) error {
+			<-release
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int, 1)
+	if err := p.Start(context.Background(), data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	data <- 1
+	deadline := time.Now().Add(time.Second)
+	for p.InFlight() != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if p.InFlight() != 1 {
+		t.Fatalf("expected InFlight == 1 while the Tour is stuck in the stage, got %d", p.InFlight())
+	}
+
+	close(release)
+	<-p.PostProcessed()
+	if p.InFlight() != 0 {
+		t.Fatalf("expected InFlight == 0 after the Tour reached PostProcessed, got %d", p.InFlight())
+	}
+}
+
+// TestReloadWithBacklogDoesNotLeakInFlight checks that Tours still sitting
+// unread in a stage's internal queue when Reload cancels the old channel are
+// retired from InFlight too, not just the one Tour actively being processed.
+func TestReloadWithBacklogDoesNotLeakInFlight(t *testing.T) {
+	release := make(chan struct{})
+	conf, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int]([]func(int) error{func(v int) error {
+			if v == 0 {
+				<-release // blocks the stage so later Tours back up behind it
+			}
+			return nil
+		}}),
+		park.WithStageTimeout[int](5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	p := park.New[int]()
+	if err := p.SetConf(conf); err != nil {
+		t.Fatalf("SetConf: %v", err)
+	}
+	data := make(chan int, 8)
+	if err := p.Start(context.Background(), data); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		data <- i
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for p.InFlight() < 6 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if p.InFlight() != 6 {
+		t.Fatalf("expected InFlight == 6 with 1 stuck and 5 backed up, got %d", p.InFlight())
+	}
+
+	conf2, err := park.NewParkConf[int](
+		park.WithNumChannels[int](1),
+		park.WithFuncs[int]([]func(int) error{func(int) error { return nil }}),
+		park.WithChannelTimeout[int](30*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewParkConf: %v", err)
+	}
+	if err := p.Reload(conf2); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	close(release)
+
+	drainDeadline := time.After(time.Second)
+	for {
+		select {
+		case <-p.PostProcessed():
+		case <-drainDeadline:
+			goto drained
+		}
+	}
+drained:
+	if p.InFlight() != 0 {
+		t.Fatalf("expected InFlight == 0 after Reload drains the backlog, got %d", p.InFlight())
+	}
+}
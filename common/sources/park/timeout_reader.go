@@ -0,0 +1,41 @@
+package park
+
+import (
+	"context"
+	"time"
+)
+
+// NewTimeoutReader wraps src so that the returned channel is closed once src
+// has produced nothing for idle, instead of a channel consumer blocking on
+// it forever. Values read from src before the idle deadline are forwarded
+// unchanged; closing ctx also stops the forwarding goroutine.
+func NewTimeoutReader[T any](ctx context.Context, src <-chan T, idle time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(idle)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				return
+			case v, ok := <-src:
+				if !ok {
+					return
+				}
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(idle)
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -1,13 +1,23 @@
 package tourist
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Tourist Options
 type TouristOptions struct {
-	concurrency    int // specify the number of workers
-	timeoutMinutes int // timeout in minutes
+	concurrency    int           // specify the number of workers
+	timeoutMinutes int           // timeout in minutes
+	maxRetries     int           // number of retries allowed per Tour before it lands in the DLQ
+	backoffBase    time.Duration // base delay before the first retry
+	backoffMax     time.Duration // cap applied to the exponential backoff
+	backoffJitter  float64       // fraction of the computed delay to randomize, e.g. 0.2 == +/-20%
 }
 
 // A setter for Single Option
@@ -19,6 +29,10 @@ func NewTouristOptions(options ...TouristOption) *TouristOptions {
 	opts := &TouristOptions{
 		concurrency:    1,
 		timeoutMinutes: 5,
+		maxRetries:     0,
+		backoffBase:    200 * time.Millisecond,
+		backoffMax:     10 * time.Second,
+		backoffJitter:  0.2,
 	}
 	// accept custom options
 	for _, option := range options {
@@ -41,6 +55,26 @@ func WithTimoutMinutes(v int) TouristOption {
 	}
 }
 
+// WithMaxRetries caps how many times a failed Tour is retried before it is
+// moved to the dead-letter queue. 0 (the default) disables retries.
+func WithMaxRetries(v int) TouristOption {
+	return func(opts *TouristOptions) {
+		opts.maxRetries = v
+	}
+}
+
+// WithBackoff sets the exponential backoff applied between retries: base is
+// the delay before the first retry, max caps the delay regardless of attempt
+// count, and jitter is the fraction of the computed delay (0..1) that is
+// randomized to avoid retry stampedes.
+func WithBackoff(base, max time.Duration, jitter float64) TouristOption {
+	return func(opts *TouristOptions) {
+		opts.backoffBase = base
+		opts.backoffMax = max
+		opts.backoffJitter = jitter
+	}
+}
+
 func (opts *TouristOptions) Concurrency() int {
 	return opts.concurrency
 }
@@ -49,6 +83,22 @@ func (opts *TouristOptions) TimeoutMinutes() int {
 	return opts.timeoutMinutes
 }
 
+func (opts *TouristOptions) MaxRetries() int {
+	return opts.maxRetries
+}
+
+func (opts *TouristOptions) BackoffBase() time.Duration {
+	return opts.backoffBase
+}
+
+func (opts *TouristOptions) BackoffMax() time.Duration {
+	return opts.backoffMax
+}
+
+func (opts *TouristOptions) BackoffJitter() float64 {
+	return opts.backoffJitter
+}
+
 // Status of workload
 type Status int
 
@@ -61,6 +111,62 @@ const (
 	TouristCanceled
 )
 
+// Sentinel errors returned by the lifecycle methods (SetOptions, Enter,
+// Start, Cancel, Reset, Pause, Resume) instead of an ad-hoc
+// errors.New("Invalid State!"), so callers can distinguish failure modes
+// with errors.Is instead of string matching.
+var (
+	// ErrAlreadyStarted is returned when an operation that requires the
+	// pool to be idle is attempted while it is TouristStarted or
+	// TouristPaused.
+	ErrAlreadyStarted = errors.New("tourist: already started")
+	// ErrAlreadyStopped is returned when an operation that requires a
+	// running pool (Pause, Resume) is attempted after the run has
+	// concluded, been canceled, or never started.
+	ErrAlreadyStopped = errors.New("tourist: already stopped")
+	// ErrNotCheckedIn is returned by Start when Enter has not been called
+	// (or Reset has cleared a prior check-in) since the last run.
+	ErrNotCheckedIn = errors.New("tourist: not checked in")
+	// ErrInvalidTransition is wrapped, together with a from->to pair, into
+	// the error a lifecycle method returns when the current Status rules
+	// the call out and none of the more specific sentinels above applies.
+	ErrInvalidTransition = errors.New("tourist: invalid state transition")
+)
+
+// tourTransitions is the single source of truth for which Status changes
+// are legal. It documents the lifecycle as much as it enforces it: Initial
+// check(s) in, a checked-in run starts, a started run can be paused,
+// resumed, canceled, or finish on its own, and everything terminal
+// (Finished, Canceled) can only be escaped by Reset back to Initial.
+var tourTransitions = map[Status][]Status{
+	// The zero Status, only ever seen on a *Tourist before its first Reset
+	// (New calls Reset to get it out of this state).
+	Status(0):        {TouristInitial},
+	TouristInitial:   {TouristInitial, TouristCheckedIn},
+	TouristCheckedIn: {TouristStarted, TouristInitial},
+	TouristStarted:   {TouristPaused, TouristCanceled, TouristFinished, TouristInitial},
+	TouristPaused:    {TouristStarted, TouristCanceled, TouristFinished, TouristInitial},
+	TouristFinished:  {TouristInitial},
+	TouristCanceled:  {TouristInitial},
+}
+
+// canTransition reports whether tourTransitions allows moving from from to
+// to.
+func canTransition(from, to Status) bool {
+	for _, allowed := range tourTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionErr wraps ErrInvalidTransition with the from->to pair that was
+// attempted.
+func transitionErr(from, to Status) error {
+	return fmt.Errorf("tourist: cannot move from %v to %v: %w", from, to, ErrInvalidTransition)
+}
+
 // Status of tour
 type TourStatus int
 
@@ -74,14 +180,28 @@ const (
 
 // The workload can be consumed by the worker
 type Tour struct {
-	name   string     // file name
-	status TourStatus //
+	name         string     // file name
+	status       TourStatus //
+	attempts     int        // number of times Visit has been attempted
+	nextEligible time.Time  // earliest time this Tour may be retried
 }
 
+// Context is handed to a Visitor on every call to Visit. It carries the
+// run's cancellation signal alongside the options the run was started
+// with, so a Visitor can give up early instead of racing Cancel.
 type Context struct {
+	ctx     context.Context
 	options *TouristOptions
 }
 
+func (c Context) Ctx() context.Context {
+	return c.ctx
+}
+
+func (c Context) Options() *TouristOptions {
+	return c.options
+}
+
 var t *Tourist
 
 func init() {
@@ -91,6 +211,8 @@ func init() {
 func New() *Tourist {
 	tourist := new(Tourist)
 	tourist.options = NewTouristOptions()
+	tourist.events = make(chan Event, eventQueueSize)
+	go tourist.dispatch()
 	tourist.Reset()
 	return tourist
 }
@@ -100,6 +222,8 @@ func Total() int {
 }
 
 func (t *Tourist) Total() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.total
 }
 
@@ -108,6 +232,8 @@ func Finished() int {
 }
 
 func (t *Tourist) Finished() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.finished
 }
 
@@ -116,6 +242,8 @@ func GetStatus() Status {
 }
 
 func (t *Tourist) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.status
 }
 
@@ -124,6 +252,8 @@ func Options() *TouristOptions {
 }
 
 func (t *Tourist) Options() *TouristOptions {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.options
 }
 
@@ -132,13 +262,37 @@ func SetOptions(options *TouristOptions) error {
 }
 
 func (t *Tourist) SetOptions(options *TouristOptions) error {
-	if t.status != TouristInitial {
-		return errors.New("Invalid State!")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.status {
+	case TouristStarted, TouristPaused:
+		return ErrAlreadyStarted
+	case TouristInitial:
+		// ok, fall through
+	default:
+		return transitionErr(t.status, TouristInitial)
 	}
 	t.options = options
 	return nil
 }
 
+// Failed returns the Tours that exhausted their retries (or were flagged by
+// a BadTargetClassifier) and landed in the dead-letter queue during the most
+// recent run.
+func Failed() []Tour {
+	return t.Failed()
+}
+
+func (t *Tourist) Failed() []Tour {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Tour, 0, len(t.failed))
+	for _, tr := range t.failed {
+		out = append(out, *tr)
+	}
+	return out
+}
+
 type Tourist struct {
 	options   *TouristOptions
 	entrance  string
@@ -146,6 +300,21 @@ type Tourist struct {
 	total     int
 	finished  int
 	workloads map[string]*Tour
+	failed    map[string]*Tour // dead-letter queue, keyed by Tour name
+
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	gate           chan struct{} // closed == running; left open to block workers while paused
+	wg             sync.WaitGroup
+	rs             *runState              // the in-flight run, if any
+	pendingRetries map[string]*time.Timer // Tours currently waiting out a backoff delay
+	workers        sync.Map               // map[int]*atomic.Pointer[WorkerSnapshot], live worker registry
+
+	events         chan Event      // the dispatcher's inbox; see event_bus.go
+	listeners      []listenerEntry // AddListener registrations, survive across runs
+	nextListenerID int
+	subs           []*subscriber // Subscribe registrations, survive across runs
+	nextSubID      int
 }
 
 type Visitor interface {
@@ -160,30 +329,177 @@ type Checker interface {
 	Check(name string) ([]string, error)
 }
 
+// BadTargetClassifier lets a caller short-circuit retries for a whole target
+// (e.g. a host or path reported unreachable by the Checker) instead of
+// paying the full backoff schedule for every Tour under it.
+type BadTargetClassifier interface {
+	IsBadTarget(t Tour, err error) bool
+}
+
+// StartOption configures a single Start call.
+type StartOption func(*startConfig)
+
+type startConfig struct {
+	classifier BadTargetClassifier
+}
+
+// WithBadTargetClassifier installs a classifier consulted on every Visit
+// failure; a Tour it flags as a bad target skips retries and goes straight
+// to the dead-letter queue.
+func WithBadTargetClassifier(c BadTargetClassifier) StartOption {
+	return func(cfg *startConfig) {
+		cfg.classifier = c
+	}
+}
+
+// runState holds the bookkeeping for a single Start..Finish run so it can be
+// threaded through workers without growing their argument lists.
+type runState struct {
+	workCh        chan *Tour
+	cctx          Context
+	visitor       Visitor
+	classifier    BadTargetClassifier
+	remaining     int32 // atomic; Tours not yet finally resolved
+	closeOnce     sync.Once
+	listenerUnsub func() // unregisters the Listener passed to Start, if any
+}
+
+func (rs *runState) finish() {
+	rs.closeOnce.Do(func() {
+		close(rs.workCh)
+	})
+}
+
 func Cancel() error {
 	return t.Cancel()
 }
 
+// Cancel stops an in-progress run: it cancels the worker pool's context,
+// releases any worker currently blocked waiting on a Pause, stops any Tour
+// waiting out a retry backoff, and waits for the pool to drain, bounded by a
+// deadline derived from TimeoutMinutes. Cancel is intentionally a no-op,
+// not an error, when the pool is not TouristStarted or TouristPaused, so
+// callers (including Reset) may call it reentrantly without checking
+// Status first.
 func (t *Tourist) Cancel() error {
+	t.mu.Lock()
+	if t.status != TouristStarted && t.status != TouristPaused {
+		t.mu.Unlock()
+		return nil
+	}
+	wasPaused := t.status == TouristPaused
+	gate := t.gate
+	cancel := t.cancel
+	rs := t.rs
+	t.status = TouristCanceled
+	if wasPaused {
+		t.gate = make(chan struct{})
+		close(t.gate)
+	}
+	var stopped []*Tour
+	for name, timer := range t.pendingRetries {
+		if timer.Stop() {
+			if tr, ok := t.workloads[name]; ok {
+				stopped = append(stopped, tr)
+			}
+		}
+		delete(t.pendingRetries, name)
+	}
+	t.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if wasPaused {
+		close(gate) // release any worker blocked waiting to resume
+	}
+	if rs != nil {
+		for _, tr := range stopped {
+			t.cancelTour(tr, rs)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Duration(t.options.TimeoutMinutes()) * time.Minute):
+	}
+	t.flush() // make sure every canceled Tour reached its listeners before we return
 	return nil
 }
 
+// Pause blocks every worker before it picks up its next Tour. Tours
+// already in progress run to completion.
+func Pause() error {
+	return t.Pause()
+}
+
+func (t *Tourist) Pause() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.status {
+	case TouristStarted:
+		t.status = TouristPaused
+		t.gate = make(chan struct{})
+		return nil
+	case TouristFinished, TouristCanceled, TouristInitial, TouristCheckedIn:
+		return ErrAlreadyStopped
+	default: // TouristPaused
+		return transitionErr(t.status, TouristPaused)
+	}
+}
+
+// Resume releases workers parked by a prior Pause.
+func Resume() error {
+	return t.Resume()
+}
+
+func (t *Tourist) Resume() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.status {
+	case TouristPaused:
+		t.status = TouristStarted
+		close(t.gate)
+		return nil
+	case TouristStarted:
+		return ErrAlreadyStarted
+	case TouristFinished, TouristCanceled, TouristInitial, TouristCheckedIn:
+		return ErrAlreadyStopped
+	default:
+		return transitionErr(t.status, TouristStarted)
+	}
+}
+
 func Reset() error {
 	return t.Reset()
 }
 
 func (t *Tourist) Reset() error {
-	if t.status == TouristPaused || t.status == TouristStarted {
+	if s := t.Status(); s == TouristPaused || s == TouristStarted {
 		err := t.Cancel()
 		if err != nil {
 			return err
 		}
 	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !canTransition(t.status, TouristInitial) {
+		return transitionErr(t.status, TouristInitial)
+	}
 	t.entrance = ""
 	t.status = TouristInitial
 	t.total = 0
 	t.finished = 0
 	t.workloads = make(map[string]*Tour, 16)
+	t.failed = make(map[string]*Tour, 0)
+	t.pendingRetries = make(map[string]*time.Timer, 0)
+	t.rs = nil
+	t.workers = sync.Map{}
 	return nil
 }
 
@@ -192,7 +508,7 @@ func Enter(entrance string, checker Checker) error {
 }
 
 func (t *Tourist) Enter(entrance string, checker Checker) error {
-	err := Reset()
+	err := t.Reset()
 	if err != nil {
 		return err
 	}
@@ -200,20 +516,279 @@ func (t *Tourist) Enter(entrance string, checker Checker) error {
 	if err != nil {
 		return err
 	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	for _, s := range sc {
 		tr := new(Tour)
 		tr.name = s
 		tr.status = TourPending
 		t.workloads[s] = tr
 	}
+	t.entrance = entrance
+	t.total = len(t.workloads)
 	t.status = TouristCheckedIn
 	return nil
 }
 
-func Start() error {
-	return t.Start()
-}
+func Start(visitor Visitor, listener Listener, opts ...StartOption) error {
+	return t.Start(visitor, listener, opts...)
+}
+
+// Start spins up Options().Concurrency() workers that pull Tours off the
+// checked-in workload and hand them to visitor, reporting each one to
+// listener as it finishes. listener is registered for this run only (see
+// AddListener) and is automatically unregistered once the run concludes;
+// callers that want a listener to survive across runs should call
+// AddListener themselves and pass nil here. A Visit that returns an error
+// is retried with exponential backoff (see WithMaxRetries, WithBackoff)
+// until it succeeds, is flagged by a BadTargetClassifier, or exhausts its
+// retries and lands in the dead-letter queue exposed by Failed. Start
+// returns once the pool is running; callers watch Status, Subscribe, or
+// call Cancel to stop a run early.
+func (t *Tourist) Start(visitor Visitor, listener Listener, opts ...StartOption) error {
+	cfg := &startConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	t.mu.Lock()
+	switch t.status {
+	case TouristCheckedIn:
+		// ok, fall through
+	case TouristStarted, TouristPaused:
+		t.mu.Unlock()
+		return ErrAlreadyStarted
+	default:
+		t.mu.Unlock()
+		return ErrNotCheckedIn
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.gate = make(chan struct{})
+	close(t.gate) // start out running, not paused
+	t.failed = make(map[string]*Tour, 0)
+	t.pendingRetries = make(map[string]*time.Timer, 0)
+
+	workCh := make(chan *Tour, len(t.workloads))
+	for _, tr := range t.workloads {
+		workCh <- tr
+	}
+
+	n := t.options.Concurrency()
+	rs := &runState{
+		workCh:     workCh,
+		cctx:       Context{ctx: ctx, options: t.options},
+		visitor:    visitor,
+		classifier: cfg.classifier,
+		remaining:  int32(len(t.workloads)),
+	}
+	if listener != nil {
+		rs.listenerUnsub = t.addListenerLocked(listener)
+	}
+	t.rs = rs
+	t.status = TouristStarted
+	t.wg.Add(n)
+	t.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		go t.work(ctx, rs, i)
+	}
 
-func (t *Tourist) Start() error {
+	go func() {
+		t.wg.Wait()
+		t.mu.Lock()
+		finished, total := t.finished, t.total
+		shouldFinish := t.status == TouristStarted || t.status == TouristPaused
+		t.mu.Unlock()
+		if shouldFinish {
+			t.emitSync(TouristFinishedEvent{Finished: finished, Total: total})
+			t.mu.Lock()
+			if t.status == TouristStarted || t.status == TouristPaused {
+				t.status = TouristFinished
+			}
+			t.mu.Unlock()
+		}
+		if rs.listenerUnsub != nil {
+			t.flush() // deliver every Tour's terminal event before unregistering listener
+			rs.listenerUnsub()
+		}
+	}()
 	return nil
 }
+
+// work is a single worker's loop: pull a Tour, wait out any Pause, run the
+// Visitor, retry or dead-letter a failure, and repeat until the queue drains
+// or ctx is canceled. It registers a WorkerSnapshot on entry, keeps it
+// current as the Tour moves through phases, and deregisters on exit so
+// WorkerInfo never reports a worker that is no longer running.
+func (t *Tourist) work(ctx context.Context, rs *runState, workerID int) {
+	defer t.wg.Done()
+	snap := new(atomic.Pointer[WorkerSnapshot])
+	snap.Store(&WorkerSnapshot{WorkerID: workerID})
+	t.workers.Store(workerID, snap)
+	defer t.workers.Delete(workerID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.drain(rs)
+			return
+		case tr, ok := <-rs.workCh:
+			if !ok {
+				return
+			}
+			t.mu.Lock()
+			gate := t.gate
+			t.mu.Unlock()
+			select {
+			case <-gate:
+			case <-ctx.Done():
+				t.cancelTour(tr, rs)
+				t.drain(rs)
+				return
+			}
+
+			tr.status = TourInProgress
+			attempt := tr.attempts + 1
+			snap.Store(&WorkerSnapshot{
+				WorkerID:  workerID,
+				Tour:      tr.name,
+				StartedAt: time.Now(),
+				Attempts:  attempt,
+				Status:    TourInProgress,
+			})
+			t.emit(TourStartedEvent{Tour: tr.name, Attempt: attempt, At: time.Now()})
+			err := rs.visitor.Visit(rs.cctx, *tr)
+			// The outcome status is derived here, from err and ctx, rather
+			// than re-read off tr after the fact: a retried Tour can be
+			// picked up by another worker the instant it is re-enqueued, so
+			// reading tr.status afterwards would race with that worker.
+			switch {
+			case ctx.Err() != nil:
+				t.cancelTour(tr, rs)
+				updateSnapshot(snap, workerID, TourCanceled, "")
+			case err == nil:
+				finished, total := t.finalize(tr, TourCompleted, rs)
+				updateSnapshot(snap, workerID, TourCompleted, "")
+				t.emit(TourSucceededEvent{Tour: tr.name, Finished: finished, Total: total})
+				t.emit(TourProgressEvent{Tour: tr.name, Percent: percentOf(finished, total)})
+			case t.retryOrDeadLetter(ctx, tr, err, rs):
+				updateSnapshot(snap, workerID, TourError, err.Error())
+			default:
+				updateSnapshot(snap, workerID, TourPending, err.Error())
+			}
+		}
+	}
+}
+
+// retryOrDeadLetter handles a failed Visit: a Tour flagged by the
+// classifier, or one that has exhausted MaxRetries, goes straight to the
+// dead-letter queue (reporting true); otherwise it is scheduled to rejoin
+// the work queue after an exponential backoff delay (reporting false).
+func (t *Tourist) retryOrDeadLetter(ctx context.Context, tr *Tour, err error, rs *runState) bool {
+	tr.attempts++
+	opts := rs.cctx.Options()
+
+	badTarget := rs.classifier != nil && rs.classifier.IsBadTarget(*tr, err)
+	if badTarget || tr.attempts > opts.MaxRetries() {
+		t.mu.Lock()
+		t.failed[tr.name] = tr
+		t.mu.Unlock()
+		finished, total := t.finalize(tr, TourError, rs)
+		t.emit(TourFailedEvent{Tour: tr.name, Err: err, Finished: finished, Total: total})
+		t.emit(TourProgressEvent{Tour: tr.name, Percent: percentOf(finished, total)})
+		return true
+	}
+
+	delay := backoffDelay(tr.attempts, opts.BackoffBase(), opts.BackoffMax(), opts.BackoffJitter())
+	tr.nextEligible = time.Now().Add(delay)
+	tr.status = TourPending
+	t.emit(TourRetriedEvent{Tour: tr.name, Attempt: tr.attempts, NextAt: tr.nextEligible})
+
+	timer := time.AfterFunc(delay, func() {
+		t.mu.Lock()
+		delete(t.pendingRetries, tr.name)
+		t.mu.Unlock()
+		select {
+		case rs.workCh <- tr:
+		case <-ctx.Done():
+			t.cancelTour(tr, rs)
+		}
+	})
+	t.mu.Lock()
+	t.pendingRetries[tr.name] = timer
+	t.mu.Unlock()
+	return false
+}
+
+// backoffDelay computes the exponential backoff for the given attempt
+// (1-indexed), capped at max and randomized by +/- jitter to avoid retry
+// stampedes across Tours.
+func backoffDelay(attempt int, base, max time.Duration, jitter float64) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if max > 0 && delay > max {
+			delay = max
+			break
+		}
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	if jitter > 0 {
+		spread := time.Duration(float64(delay) * jitter)
+		if spread > 0 {
+			delay += time.Duration(rand.Int63n(int64(2*spread+1))) - spread
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// drain marks every Tour left on rs.workCh as canceled without visiting it,
+// so a Cancel mid-run still accounts for every workload entry. Tours
+// waiting out a retry backoff are handled separately by Cancel.
+func (t *Tourist) drain(rs *runState) {
+	for {
+		select {
+		case tr, ok := <-rs.workCh:
+			if !ok {
+				return
+			}
+			t.cancelTour(tr, rs)
+		default:
+			return
+		}
+	}
+}
+
+// finalize records a Tour's terminal outcome and closes the work channel
+// once every Tour in the run has resolved so idle workers can exit. It
+// returns the run's finished/total counts at the moment tr resolved, for
+// callers that go on to emit an Event carrying the same numbers.
+func (t *Tourist) finalize(tr *Tour, status TourStatus, rs *runState) (finished, total int) {
+	tr.status = status
+	t.mu.Lock()
+	t.finished++
+	finished, total = t.finished, t.total
+	t.mu.Unlock()
+	if atomic.AddInt32(&rs.remaining, -1) == 0 {
+		rs.finish()
+	}
+	return finished, total
+}
+
+// cancelTour finalizes tr as TourCanceled and emits the matching Event. It
+// is the common path used by Cancel, a ctx-canceled worker, drain, and a
+// retry timer that fires after the run was already canceled.
+func (t *Tourist) cancelTour(tr *Tour, rs *runState) {
+	finished, total := t.finalize(tr, TourCanceled, rs)
+	t.emit(TourCanceledEvent{Tour: tr.name, Finished: finished, Total: total})
+}
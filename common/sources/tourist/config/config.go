@@ -0,0 +1,137 @@
+// Package config loads tourist.TouristOptions from external sources. The
+// base package only depends on the standard library (LoadOptionsEnv); the
+// toml and yaml subpackages pull in their respective third-party decoders,
+// so a caller that only ever reads options from the environment never has
+// to vendor a TOML or YAML library transitively.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	tourist "github.com/sharework-cn/smtools/common/sources"
+)
+
+// Config is the tagged, on-disk/env representation of a
+// tourist.TouristOptions. Every loader (env here, TOML/YAML in the
+// sibling subpackages) decodes into a Config, calls Validate, and then
+// ToOptions to hand back the *tourist.TouristOptions SetOptions expects.
+type Config struct {
+	Concurrency    int     `toml:"concurrency" yaml:"concurrency"`
+	TimeoutMinutes int     `toml:"timeout_minutes" yaml:"timeout_minutes"`
+	MaxRetries     int     `toml:"max_retries" yaml:"max_retries"`
+	BackoffBaseMS  int     `toml:"backoff_base_ms" yaml:"backoff_base_ms"`
+	BackoffMaxMS   int     `toml:"backoff_max_ms" yaml:"backoff_max_ms"`
+	BackoffJitter  float64 `toml:"backoff_jitter" yaml:"backoff_jitter"`
+}
+
+// DefaultConfig mirrors the defaults NewTouristOptions applies, so a
+// loader only has to override the fields its source actually specifies.
+func DefaultConfig() Config {
+	defaults := tourist.NewTouristOptions()
+	return Config{
+		Concurrency:    defaults.Concurrency(),
+		TimeoutMinutes: defaults.TimeoutMinutes(),
+		MaxRetries:     defaults.MaxRetries(),
+		BackoffBaseMS:  int(defaults.BackoffBase() / time.Millisecond),
+		BackoffMaxMS:   int(defaults.BackoffMax() / time.Millisecond),
+		BackoffJitter:  defaults.BackoffJitter(),
+	}
+}
+
+// Validate enforces the invariants TouristOptions itself relies on:
+// Concurrency must be at least 1 and TimeoutMinutes must be positive. Every
+// loader calls this before handing back a *tourist.TouristOptions.
+func (c Config) Validate() error {
+	if c.Concurrency < 1 {
+		return fmt.Errorf("config: concurrency must be >= 1, got %d", c.Concurrency)
+	}
+	if c.TimeoutMinutes <= 0 {
+		return fmt.Errorf("config: timeout_minutes must be > 0, got %d", c.TimeoutMinutes)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("config: max_retries must be >= 0, got %d", c.MaxRetries)
+	}
+	if c.BackoffBaseMS < 0 || c.BackoffMaxMS < 0 {
+		return fmt.Errorf("config: backoff_base_ms and backoff_max_ms must be >= 0")
+	}
+	if c.BackoffJitter < 0 || c.BackoffJitter > 1 {
+		return fmt.Errorf("config: backoff_jitter must be within [0, 1], got %f", c.BackoffJitter)
+	}
+	return nil
+}
+
+// ToOptions builds the *tourist.TouristOptions this Config describes. It
+// assumes Validate has already passed.
+func (c Config) ToOptions() *tourist.TouristOptions {
+	return tourist.NewTouristOptions(
+		tourist.WithConcurrency(c.Concurrency),
+		tourist.WithTimoutMinutes(c.TimeoutMinutes),
+		tourist.WithMaxRetries(c.MaxRetries),
+		tourist.WithBackoff(
+			time.Duration(c.BackoffBaseMS)*time.Millisecond,
+			time.Duration(c.BackoffMaxMS)*time.Millisecond,
+			c.BackoffJitter,
+		),
+	)
+}
+
+// LoadOptionsEnv builds a *tourist.TouristOptions from the environment
+// variables <prefix>CONCURRENCY, <prefix>TIMEOUT_MINUTES,
+// <prefix>MAX_RETRIES, <prefix>BACKOFF_BASE_MS, <prefix>BACKOFF_MAX_MS and
+// <prefix>BACKOFF_JITTER. A variable left unset keeps its NewTouristOptions
+// default.
+func LoadOptionsEnv(prefix string) (*tourist.TouristOptions, error) {
+	cfg := DefaultConfig()
+
+	fields := []struct {
+		suffix string
+		set    func(string) error
+	}{
+		{"CONCURRENCY", intSetter(&cfg.Concurrency)},
+		{"TIMEOUT_MINUTES", intSetter(&cfg.TimeoutMinutes)},
+		{"MAX_RETRIES", intSetter(&cfg.MaxRetries)},
+		{"BACKOFF_BASE_MS", intSetter(&cfg.BackoffBaseMS)},
+		{"BACKOFF_MAX_MS", intSetter(&cfg.BackoffMaxMS)},
+		{"BACKOFF_JITTER", floatSetter(&cfg.BackoffJitter)},
+	}
+	for _, f := range fields {
+		name := prefix + f.suffix
+		v, ok := os.LookupEnv(name)
+		if !ok || v == "" {
+			continue
+		}
+		if err := f.set(v); err != nil {
+			return nil, fmt.Errorf("config: env %s: %w", name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg.ToOptions(), nil
+}
+
+func intSetter(dst *int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func floatSetter(dst *float64) func(string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*dst = f
+		return nil
+	}
+}
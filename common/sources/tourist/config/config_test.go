@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestLoadOptionsEnvAppliesEveryFieldWithAPrefix(t *testing.T) {
+	const prefix = "TOURIST_ROUNDTRIP_"
+	env := map[string]string{
+		prefix + "CONCURRENCY":     "8",
+		prefix + "TIMEOUT_MINUTES": "10",
+		prefix + "MAX_RETRIES":     "4",
+		prefix + "BACKOFF_BASE_MS": "250",
+		prefix + "BACKOFF_MAX_MS":  "5000",
+		prefix + "BACKOFF_JITTER":  "0.3",
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	opts, err := LoadOptionsEnv(prefix)
+	if err != nil {
+		t.Fatalf("LoadOptionsEnv: %v", err)
+	}
+	if got := opts.Concurrency(); got != 8 {
+		t.Fatalf("Concurrency = %d, want 8", got)
+	}
+	if got := opts.TimeoutMinutes(); got != 10 {
+		t.Fatalf("TimeoutMinutes = %d, want 10", got)
+	}
+	if got := opts.MaxRetries(); got != 4 {
+		t.Fatalf("MaxRetries = %d, want 4", got)
+	}
+	if got := opts.BackoffBase(); got.Milliseconds() != 250 {
+		t.Fatalf("BackoffBase = %v, want 250ms", got)
+	}
+	if got := opts.BackoffMax(); got.Milliseconds() != 5000 {
+		t.Fatalf("BackoffMax = %v, want 5000ms", got)
+	}
+	if got := opts.BackoffJitter(); got != 0.3 {
+		t.Fatalf("BackoffJitter = %v, want 0.3", got)
+	}
+}
+
+func TestLoadOptionsEnvLeavesUnsetFieldsAtTheirDefault(t *testing.T) {
+	opts, err := LoadOptionsEnv("TOURIST_UNSET_")
+	if err != nil {
+		t.Fatalf("LoadOptionsEnv: %v", err)
+	}
+	defaults := DefaultConfig()
+	if got := opts.Concurrency(); got != defaults.Concurrency {
+		t.Fatalf("Concurrency = %d, want default %d", got, defaults.Concurrency)
+	}
+	if got := opts.TimeoutMinutes(); got != defaults.TimeoutMinutes {
+		t.Fatalf("TimeoutMinutes = %d, want default %d", got, defaults.TimeoutMinutes)
+	}
+}
+
+func TestLoadOptionsEnvRejectsInvalidConcurrency(t *testing.T) {
+	t.Setenv("TOURIST_BAD_CONCURRENCY", "0")
+	if _, err := LoadOptionsEnv("TOURIST_BAD_"); err == nil {
+		t.Fatalf("expected an error for concurrency=0")
+	}
+}
+
+func TestLoadOptionsEnvRejectsUnparsableValue(t *testing.T) {
+	t.Setenv("TOURIST_GARBLED_CONCURRENCY", "not-a-number")
+	if _, err := LoadOptionsEnv("TOURIST_GARBLED_"); err == nil {
+		t.Fatalf("expected an error for an unparsable concurrency")
+	}
+}
@@ -0,0 +1,27 @@
+// Package toml loads tourist.TouristOptions from a TOML document. It is
+// split out from the parent config package so that reading options from
+// the environment (or from YAML) never pulls in a TOML decoder.
+package toml
+
+import (
+	"io"
+
+	gotoml "github.com/pelletier/go-toml/v2"
+
+	tourist "github.com/sharework-cn/smtools/common/sources"
+	"github.com/sharework-cn/smtools/common/sources/tourist/config"
+)
+
+// LoadOptionsTOML decodes r as TOML into a config.Config, validates it, and
+// returns the equivalent *tourist.TouristOptions. Fields the document
+// omits keep their config.DefaultConfig value.
+func LoadOptionsTOML(r io.Reader) (*tourist.TouristOptions, error) {
+	cfg := config.DefaultConfig()
+	if err := gotoml.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg.ToOptions(), nil
+}
@@ -0,0 +1,27 @@
+// Package yaml loads tourist.TouristOptions from a YAML document. It is
+// split out from the parent config package so that reading options from
+// the environment (or from TOML) never pulls in a YAML decoder.
+package yaml
+
+import (
+	"io"
+
+	goyaml "gopkg.in/yaml.v3"
+
+	tourist "github.com/sharework-cn/smtools/common/sources"
+	"github.com/sharework-cn/smtools/common/sources/tourist/config"
+)
+
+// LoadOptionsYAML decodes r as YAML into a config.Config, validates it, and
+// returns the equivalent *tourist.TouristOptions. Fields the document
+// omits keep their config.DefaultConfig value.
+func LoadOptionsYAML(r io.Reader) (*tourist.TouristOptions, error) {
+	cfg := config.DefaultConfig()
+	if err := goyaml.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg.ToOptions(), nil
+}
@@ -0,0 +1,46 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadOptionsYAMLRoundTripsEveryField(t *testing.T) {
+	doc := strings.NewReader(`
+concurrency: 8
+timeout_minutes: 10
+max_retries: 4
+backoff_base_ms: 250
+backoff_max_ms: 5000
+backoff_jitter: 0.3
+`)
+	opts, err := LoadOptionsYAML(doc)
+	if err != nil {
+		t.Fatalf("LoadOptionsYAML: %v", err)
+	}
+	if got := opts.Concurrency(); got != 8 {
+		t.Fatalf("Concurrency = %d, want 8", got)
+	}
+	if got := opts.TimeoutMinutes(); got != 10 {
+		t.Fatalf("TimeoutMinutes = %d, want 10", got)
+	}
+	if got := opts.MaxRetries(); got != 4 {
+		t.Fatalf("MaxRetries = %d, want 4", got)
+	}
+	if got := opts.BackoffBase().Milliseconds(); got != 250 {
+		t.Fatalf("BackoffBase = %dms, want 250ms", got)
+	}
+	if got := opts.BackoffMax().Milliseconds(); got != 5000 {
+		t.Fatalf("BackoffMax = %dms, want 5000ms", got)
+	}
+	if got := opts.BackoffJitter(); got != 0.3 {
+		t.Fatalf("BackoffJitter = %v, want 0.3", got)
+	}
+}
+
+func TestLoadOptionsYAMLRejectsInvalidConcurrency(t *testing.T) {
+	doc := strings.NewReader("concurrency: 0\n")
+	if _, err := LoadOptionsYAML(doc); err == nil {
+		t.Fatalf("expected an error for concurrency=0")
+	}
+}
@@ -0,0 +1,358 @@
+package tourist
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubChecker struct {
+	names []string
+}
+
+func (c stubChecker) Check(entrance string) ([]string, error) {
+	return c.names, nil
+}
+
+type stubVisitor struct {
+	delay func() time.Duration
+	visit func(Context, Tour) error
+}
+
+func (v stubVisitor) Visit(ctx Context, tr Tour) error {
+	if v.delay != nil {
+		select {
+		case <-time.After(v.delay()):
+		case <-ctx.Ctx().Done():
+			return ctx.Ctx().Err()
+		}
+	}
+	if v.visit != nil {
+		return v.visit(ctx, tr)
+	}
+	return nil
+}
+
+type collectingListener struct {
+	mu   sync.Mutex
+	seen []Tour
+}
+
+func (l *collectingListener) OnNoticed(tr Tour, finished int, total int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seen = append(l.seen, tr)
+}
+
+func (l *collectingListener) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.seen)
+}
+
+func TestStartRunsEveryWorkloadEntryThroughTheVisitor(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(4))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a", "b", "c"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	if err := tourist.Start(stubVisitor{}, listener); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for tourist.Status() != TouristFinished {
+		select {
+		case <-deadline:
+			t.Fatalf("tourist never finished, status=%v", tourist.Status())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if got := listener.count(); got != 3 {
+		t.Fatalf("expected 3 tours reported, got %d", got)
+	}
+	if got := tourist.Finished(); got != 3 {
+		t.Fatalf("expected Finished()==3, got %d", got)
+	}
+}
+
+func TestCancelDrainsPendingToursAndStopsInFlightOnes(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1), WithTimoutMinutes(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"slow", "never-runs"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	visitor := stubVisitor{delay: func() time.Duration { return time.Second }}
+	if err := tourist.Start(visitor, listener); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond) // let the worker pick up the first Tour
+
+	if err := tourist.Cancel(); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if got := tourist.Status(); got != TouristCanceled {
+		t.Fatalf("expected TouristCanceled, got %v", got)
+	}
+	if got := listener.count(); got != 2 {
+		t.Fatalf("expected both tours reported after cancel, got %d", got)
+	}
+	for _, tr := range listener.seen {
+		if tr.status != TourCanceled {
+			t.Fatalf("expected TourCanceled, got %v", tr.status)
+		}
+	}
+}
+
+func TestPauseBlocksNewWorkUntilResume(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	if err := tourist.Pause(); err == nil {
+		t.Fatalf("expected Pause before Start to fail")
+	}
+
+	if err := tourist.Start(stubVisitor{}, listener); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := tourist.Pause(); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if got := tourist.Status(); got != TouristPaused {
+		t.Fatalf("expected TouristPaused, got %v", got)
+	}
+	if err := tourist.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for tourist.Status() != TouristFinished {
+		select {
+		case <-deadline:
+			t.Fatalf("tourist never finished after resume, status=%v", tourist.Status())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// failNTimesVisitor fails the first n Visits for a given Tour name, then
+// succeeds, so tests can exercise retry-then-recover without a real target.
+type failNTimesVisitor struct {
+	n int
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (v *failNTimesVisitor) Visit(ctx Context, tr Tour) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.attempts == nil {
+		v.attempts = make(map[string]int)
+	}
+	v.attempts[tr.name]++
+	if v.attempts[tr.name] <= v.n {
+		return errors.New("transient failure")
+	}
+	return nil
+}
+
+type alwaysFailVisitor struct{}
+
+func (alwaysFailVisitor) Visit(ctx Context, tr Tour) error {
+	return errors.New("permanent failure")
+}
+
+func waitForFinished(t *testing.T, tourist *Tourist) {
+	t.Helper()
+	deadline := time.After(time.Second)
+	for tourist.Status() != TouristFinished {
+		select {
+		case <-deadline:
+			t.Fatalf("tourist never finished, status=%v", tourist.Status())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestFailedVisitIsRetriedAndEventuallySucceeds(t *testing.T) {
+	tourist := New()
+	opts := NewTouristOptions(
+		WithConcurrency(2),
+		WithMaxRetries(3),
+		WithBackoff(5*time.Millisecond, 20*time.Millisecond, 0),
+	)
+	if err := tourist.SetOptions(opts); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"flaky"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	visitor := &failNTimesVisitor{n: 2}
+	if err := tourist.Start(visitor, listener); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	if got := listener.count(); got != 1 {
+		t.Fatalf("expected exactly 1 terminal report, got %d", got)
+	}
+	if got := listener.seen[0].status; got != TourCompleted {
+		t.Fatalf("expected TourCompleted after retries, got %v", got)
+	}
+	if got := tourist.Failed(); len(got) != 0 {
+		t.Fatalf("expected no dead-letter entries, got %d", len(got))
+	}
+}
+
+func TestFailedVisitLandsInDeadLetterAfterExhaustingRetries(t *testing.T) {
+	tourist := New()
+	opts := NewTouristOptions(
+		WithConcurrency(2),
+		WithMaxRetries(2),
+		WithBackoff(5*time.Millisecond, 10*time.Millisecond, 0),
+	)
+	if err := tourist.SetOptions(opts); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"broken"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	if err := tourist.Start(alwaysFailVisitor{}, listener); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	if got := listener.count(); got != 1 {
+		t.Fatalf("expected exactly 1 terminal report, got %d", got)
+	}
+	if got := listener.seen[0].status; got != TourError {
+		t.Fatalf("expected TourError after exhausting retries, got %v", got)
+	}
+	failed := tourist.Failed()
+	if len(failed) != 1 || failed[0].name != "broken" {
+		t.Fatalf("expected \"broken\" in the dead-letter queue, got %+v", failed)
+	}
+}
+
+type blockAllClassifier struct{}
+
+func (blockAllClassifier) IsBadTarget(tr Tour, err error) bool {
+	return true
+}
+
+func TestBadTargetClassifierSkipsRetries(t *testing.T) {
+	tourist := New()
+	opts := NewTouristOptions(
+		WithConcurrency(1),
+		WithMaxRetries(5),
+		WithBackoff(5*time.Millisecond, 10*time.Millisecond, 0),
+	)
+	if err := tourist.SetOptions(opts); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"blocked"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	listener := &collectingListener{}
+	visitor := &failNTimesVisitor{n: 100}
+	if err := tourist.Start(visitor, listener, WithBadTargetClassifier(blockAllClassifier{})); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	visitor.mu.Lock()
+	attempts := visitor.attempts["blocked"]
+	visitor.mu.Unlock()
+	if attempts != 1 {
+		t.Fatalf("expected a bad target to be visited exactly once, got %d attempts", attempts)
+	}
+	if got := tourist.Failed(); len(got) != 1 {
+		t.Fatalf("expected the bad target in the dead-letter queue, got %d", len(got))
+	}
+}
+
+func TestStartTwiceReportsErrAlreadyStarted(t *testing.T) {
+	tourist := New()
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if err := tourist.Start(stubVisitor{delay: func() time.Duration { return time.Second }}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := tourist.Start(stubVisitor{}, nil); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("expected ErrAlreadyStarted, got %v", err)
+	}
+	if err := tourist.Cancel(); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+}
+
+func TestStartBeforeEnterReportsErrNotCheckedIn(t *testing.T) {
+	tourist := New()
+	if err := tourist.Start(stubVisitor{}, nil); !errors.Is(err, ErrNotCheckedIn) {
+		t.Fatalf("expected ErrNotCheckedIn, got %v", err)
+	}
+}
+
+func TestPauseAndResumeReportErrAlreadyStopped(t *testing.T) {
+	tourist := New()
+	if err := tourist.Pause(); !errors.Is(err, ErrAlreadyStopped) {
+		t.Fatalf("expected ErrAlreadyStopped from Pause, got %v", err)
+	}
+	if err := tourist.Resume(); !errors.Is(err, ErrAlreadyStopped) {
+		t.Fatalf("expected ErrAlreadyStopped from Resume, got %v", err)
+	}
+}
+
+func TestResumeWhileRunningReportsErrAlreadyStarted(t *testing.T) {
+	tourist := New()
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if err := tourist.Start(stubVisitor{delay: func() time.Duration { return time.Second }}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := tourist.Resume(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("expected ErrAlreadyStarted, got %v", err)
+	}
+	if err := tourist.Cancel(); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+}
+
+func TestSetOptionsAfterStartReportsErrAlreadyStarted(t *testing.T) {
+	tourist := New()
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if err := tourist.Start(stubVisitor{delay: func() time.Duration { return time.Second }}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := tourist.SetOptions(NewTouristOptions()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("expected ErrAlreadyStarted, got %v", err)
+	}
+	if err := tourist.Cancel(); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package tourist
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerSnapshot is a point-in-time view of one worker goroutine started by
+// Start: the Tour it is currently handling (if any), when it picked that
+// Tour up, how many times that Tour has been attempted, and the error from
+// its most recent failed attempt. WorkerInfo reads these lock-free off an
+// atomic pointer, so taking a snapshot never blocks a worker.
+type WorkerSnapshot struct {
+	WorkerID  int        `json:"worker_id"`
+	Tour      string     `json:"tour,omitempty"`
+	StartedAt time.Time  `json:"started_at,omitempty"`
+	Attempts  int        `json:"attempts,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Status    TourStatus `json:"status"`
+	// Stuck reports whether the current Tour has been in progress longer
+	// than Options().TimeoutMinutes(); it is computed on read, not stored.
+	Stuck bool `json:"stuck,omitempty"`
+}
+
+// updateSnapshot replaces a worker's snapshot with one carrying a new
+// Status (and, if non-empty, a new LastError), preserving the Tour,
+// StartedAt and Attempts recorded when the worker picked up its current
+// Tour.
+func updateSnapshot(snap *atomic.Pointer[WorkerSnapshot], workerID int, status TourStatus, lastErr string) {
+	ws := WorkerSnapshot{WorkerID: workerID, Status: status}
+	if prev := snap.Load(); prev != nil {
+		ws.Tour = prev.Tour
+		ws.StartedAt = prev.StartedAt
+		ws.Attempts = prev.Attempts
+		ws.LastError = prev.LastError
+	}
+	if lastErr != "" {
+		ws.LastError = lastErr
+	}
+	snap.Store(&ws)
+}
+
+// Stats aggregates the workload by TourStatus.
+type Stats struct {
+	Pending    int `json:"pending"`
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Error      int `json:"error"`
+	Canceled   int `json:"canceled"`
+}
+
+// WorkerInfo reports a snapshot of every worker goroutine currently running,
+// ordered by WorkerID. A worker whose current Tour has been in progress
+// longer than Options().TimeoutMinutes() is flagged Stuck.
+func WorkerInfo() []WorkerSnapshot {
+	return t.WorkerInfo()
+}
+
+func (t *Tourist) WorkerInfo() []WorkerSnapshot {
+	timeout := time.Duration(t.Options().TimeoutMinutes()) * time.Minute
+	out := make([]WorkerSnapshot, 0)
+	t.workers.Range(func(_, value any) bool {
+		snap := value.(*atomic.Pointer[WorkerSnapshot])
+		ws := *snap.Load()
+		if ws.Status == TourInProgress && !ws.StartedAt.IsZero() && timeout > 0 && time.Since(ws.StartedAt) > timeout {
+			ws.Stuck = true
+		}
+		out = append(out, ws)
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].WorkerID < out[j].WorkerID })
+	return out
+}
+
+// GetStats reports how many Tours in the current workload are in each
+// TourStatus.
+func GetStats() Stats {
+	return t.Stats()
+}
+
+func (t *Tourist) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var s Stats
+	for _, tr := range t.workloads {
+		switch tr.status {
+		case TourPending:
+			s.Pending++
+		case TourInProgress:
+			s.InProgress++
+		case TourCompleted:
+			s.Completed++
+		case TourError:
+			s.Error++
+		case TourCanceled:
+			s.Canceled++
+		}
+	}
+	return s
+}
+
+// workerInfoReport is the JSON body served by WorkerInfoHandler.
+type workerInfoReport struct {
+	Stats   Stats            `json:"stats"`
+	Workers []WorkerSnapshot `json:"workers"`
+}
+
+// WorkerInfoHandler returns an http.Handler serving Stats and WorkerInfo as
+// JSON on GET /, for wiring into an ops dashboard.
+func WorkerInfoHandler() http.Handler {
+	return t.WorkerInfoHandler()
+}
+
+func (t *Tourist) WorkerInfoHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(workerInfoReport{
+			Stats:   t.Stats(),
+			Workers: t.WorkerInfo(),
+		})
+	})
+	return mux
+}
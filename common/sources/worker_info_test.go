@@ -0,0 +1,93 @@
+package tourist
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorkerInfoReportsTheTourInProgress(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"slow"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+
+	release := make(chan struct{})
+	visitor := stubVisitor{visit: func(Context, Tour) error {
+		<-release
+		return nil
+	}}
+	if err := tourist.Start(visitor, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var info []WorkerSnapshot
+	deadline := time.After(time.Second)
+	for {
+		info = tourist.WorkerInfo()
+		if len(info) == 1 && info[0].Tour == "slow" {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("worker never reported tour \"slow\" in progress, got %+v", info)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if info[0].Status != TourInProgress {
+		t.Fatalf("expected TourInProgress, got %v", info[0].Status)
+	}
+	if info[0].Attempts != 1 {
+		t.Fatalf("expected first attempt, got %d", info[0].Attempts)
+	}
+
+	if got := tourist.Stats().InProgress; got != 1 {
+		t.Fatalf("expected Stats().InProgress==1, got %d", got)
+	}
+
+	close(release)
+	waitForFinished(t, tourist)
+
+	if got := tourist.WorkerInfo(); len(got) != 0 {
+		t.Fatalf("expected workers to deregister once the run finished, got %+v", got)
+	}
+	if got := tourist.Stats().Completed; got != 1 {
+		t.Fatalf("expected Stats().Completed==1, got %d", got)
+	}
+}
+
+func TestWorkerInfoHandlerServesStatsAndWorkers(t *testing.T) {
+	tourist := New()
+	if err := tourist.SetOptions(NewTouristOptions(WithConcurrency(1))); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	if err := tourist.Enter("testdata", stubChecker{names: []string{"a"}}); err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if err := tourist.Start(stubVisitor{}, nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForFinished(t, tourist)
+
+	if tourist.WorkerInfoHandler() == nil {
+		t.Fatalf("expected a non-nil handler")
+	}
+
+	rec := httptest.NewRecorder()
+	tourist.WorkerInfoHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var report workerInfoReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode handler body: %v", err)
+	}
+	if report.Stats.Completed != 1 {
+		t.Fatalf("expected stats.completed==1, got %d", report.Stats.Completed)
+	}
+	if report.Workers == nil || len(report.Workers) != 0 {
+		t.Fatalf("expected an empty workers list once the run finished, got %+v", report.Workers)
+	}
+}
@@ -0,0 +1,68 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("azurekv", func(u *url.URL) (Provider, error) {
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "create azure credential")
+		}
+		vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", u.Host)
+		client, err := azsecrets.NewClient(vaultURL, cred, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "create azure key vault client")
+		}
+		return &azureProvider{client: client}, nil
+	})
+}
+
+// azureProvider stores the key, hex-encoded, as an Azure Key Vault secret.
+// A ref looks like azurekv://myvault/sm4-foo, where the host names the vault
+// and the path (minus the leading slash) names the secret.
+type azureProvider struct {
+	client *azsecrets.Client
+}
+
+func (p *azureProvider) secretName(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "parse ref")
+	}
+	return strings.TrimPrefix(u.Path, "/"), nil
+}
+
+func (p *azureProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	name, err := p.secretName(ref)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.GetSecret(ctx, name, "", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get secret %q", name)
+	}
+	if resp.Value == nil {
+		return nil, errors.Errorf("secret %q has no value", name)
+	}
+	return hex.DecodeString(*resp.Value)
+}
+
+func (p *azureProvider) Store(ctx context.Context, ref string, key []byte) error {
+	name, err := p.secretName(ref)
+	if err != nil {
+		return err
+	}
+	v := hex.EncodeToString(key)
+	_, err = p.client.SetSecret(ctx, name, azsecrets.SetSecretParameters{Value: &v}, nil)
+	return errors.Wrapf(err, "set secret %q", name)
+}
@@ -0,0 +1,52 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("env", func(u *url.URL) (Provider, error) {
+		return &envProvider{}, nil
+	})
+}
+
+// envProvider reads/writes the key, hex-encoded, from an environment
+// variable named by the ref's host (e.g. env://SM4_KEY).
+type envProvider struct{}
+
+func (p *envProvider) name(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "parse ref")
+	}
+	return u.Host, nil
+}
+
+func (p *envProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	name, err := p.name(ref)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, errors.Errorf("environment variable %q is not set", name)
+	}
+	key, err := hex.DecodeString(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode %q", name)
+	}
+	return key, nil
+}
+
+func (p *envProvider) Store(ctx context.Context, ref string, key []byte) error {
+	name, err := p.name(ref)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(ErrNotSupported, "env provider can not persist %q for the running process", name)
+}
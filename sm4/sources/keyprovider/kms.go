@@ -0,0 +1,132 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("kms", func(u *url.URL) (Provider, error) {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "load aws config")
+		}
+		return &kmsProvider{client: kms.NewFromConfig(cfg), cacheDir: defaultKMSCacheDir()}, nil
+	})
+}
+
+// kmsClient is the subset of *kms.Client kmsProvider depends on, so tests
+// can substitute a stub instead of talking to real AWS KMS.
+type kmsClient interface {
+	Encrypt(ctx context.Context, in *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error)
+	Decrypt(ctx context.Context, in *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// defaultKMSCacheDir is where kmsProvider caches the ciphertext KMS.Encrypt
+// returns, falling back to the OS temp dir if the user cache dir can't be
+// determined.
+func defaultKMSCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil || dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "smtools", "kms")
+}
+
+// kmsProvider uses an AWS KMS key to encrypt/decrypt the SM4 key. KMS
+// itself only wraps and unwraps a blob, it has nowhere to fetch one back
+// from later, so the ciphertext Encrypt returns is hex-encoded and cached
+// in a local file under cacheDir, keyed by ref, for Fetch to read back and
+// hand to Decrypt. The ref's host+path is the KMS key ID or alias (e.g.
+// kms://alias/sm4-keys/foo).
+//
+// Unlike vaultProvider, swiftProvider and azureProvider, which all store the
+// wrapped secret itself in a shared backend, this cache is local to the host
+// that called Store: a kms:// ref only resolves on a different host if
+// cacheDir is itself on shared storage (e.g. a network filesystem mounted at
+// the same path on every host that needs it). There's no AWS-hosted place to
+// keep KMS ciphertext, so a kms:// ref is host-local by default; callers who
+// need a ref that resolves anywhere should address the key through vault://,
+// swift:// or azurekv:// instead.
+type kmsProvider struct {
+	client   kmsClient
+	cacheDir string
+}
+
+func (p *kmsProvider) keyID(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "parse ref")
+	}
+	return strings.TrimPrefix(u.Host+u.Path, "/"), nil
+}
+
+// cachePath returns the local file kmsProvider caches ref's ciphertext in.
+func (p *kmsProvider) cachePath(ref string) (string, error) {
+	keyID, err := p.keyID(ref)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(p.cacheDir, keyID), nil
+}
+
+func (p *kmsProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	keyID, err := p.keyID(ref)
+	if err != nil {
+		return nil, err
+	}
+	path, err := p.cachePath(ref)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read cached ciphertext %q (kms:// refs only resolve on the host, or shared cacheDir, that ran Store)", path)
+	}
+	ciphertext, err := hex.DecodeString(string(cached))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode cached ciphertext %q", path)
+	}
+	out, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypt via kms key %q", keyID)
+	}
+	return out.Plaintext, nil
+}
+
+func (p *kmsProvider) Store(ctx context.Context, ref string, key []byte) error {
+	keyID, err := p.keyID(ref)
+	if err != nil {
+		return err
+	}
+	out, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: key,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "encrypt via kms key %q", keyID)
+	}
+	path, err := p.cachePath(ref)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrapf(err, "create cache dir for %q", path)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(out.CiphertextBlob)), 0600); err != nil {
+		return errors.Wrapf(err, "write cached ciphertext %q", path)
+	}
+	return nil
+}
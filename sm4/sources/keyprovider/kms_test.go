@@ -0,0 +1,53 @@
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// stubKMSClient fakes AWS KMS's envelope encryption in memory: Encrypt just
+// tags the plaintext with a marker so Decrypt can recover it, without
+// needing real AWS credentials.
+type stubKMSClient struct{}
+
+const stubKMSCiphertextPrefix = "stub-ciphertext:"
+
+func (stubKMSClient) Encrypt(ctx context.Context, in *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error) {
+	return &kms.EncryptOutput{
+		CiphertextBlob: append([]byte(stubKMSCiphertextPrefix), in.Plaintext...),
+	}, nil
+}
+
+func (stubKMSClient) Decrypt(ctx context.Context, in *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	return &kms.DecryptOutput{
+		Plaintext: bytes.TrimPrefix(in.CiphertextBlob, []byte(stubKMSCiphertextPrefix)),
+	}, nil
+}
+
+func TestKMSProviderRoundTripsThroughLocalCiphertextCache(t *testing.T) {
+	p := &kmsProvider{client: stubKMSClient{}, cacheDir: t.TempDir()}
+	ctx := context.Background()
+	ref := "kms://alias/sm4-keys/foo"
+	key := bytes.Repeat([]byte{0x42}, KeySize)
+
+	if err := p.Store(ctx, ref, key); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	got, err := p.Fetch(ctx, ref)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Fatalf("fetched key = %x, want %x", got, key)
+	}
+}
+
+func TestKMSProviderFetchBeforeStoreFails(t *testing.T) {
+	p := &kmsProvider{client: stubKMSClient{}, cacheDir: t.TempDir()}
+	if _, err := p.Fetch(context.Background(), "kms://alias/sm4-keys/never-stored"); err == nil {
+		t.Fatal("expected an error fetching a ref that was never stored")
+	}
+}
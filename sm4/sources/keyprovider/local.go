@@ -0,0 +1,55 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("file", func(u *url.URL) (Provider, error) {
+		return &localProvider{}, nil
+	})
+}
+
+// localProvider stores the key, hex-encoded, in a single file named by the
+// ref's path (e.g. file:///etc/smtools/key).
+type localProvider struct{}
+
+func (p *localProvider) path(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "parse ref")
+	}
+	return u.Path, nil
+}
+
+func (p *localProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	path, err := p.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q", path)
+	}
+	key, err := hex.DecodeString(string(b))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode %q", path)
+	}
+	return key, nil
+}
+
+func (p *localProvider) Store(ctx context.Context, ref string, key []byte) error {
+	path, err := p.path(ref)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		return errors.Wrapf(err, "write %q", path)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package keyprovider
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("mock", func(u *url.URL) (Provider, error) {
+		return mockShared, nil
+	})
+}
+
+// mockShared is the Provider backing the "mock://" scheme; every mock ref
+// shares the same in-memory store so tests can Store then Fetch across
+// separate Store and Fetch calls.
+var mockShared = NewMockProvider()
+
+// MockProvider is an in-memory Provider for tests. It is safe for
+// concurrent use.
+type MockProvider struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+// NewMockProvider returns an empty MockProvider.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{keys: make(map[string][]byte)}
+}
+
+func (p *MockProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key, ok := p.keys[ref]
+	if !ok {
+		return nil, errors.Errorf("mock: no key stored for %q", ref)
+	}
+	cp := make([]byte, len(key))
+	copy(cp, key)
+	return cp, nil
+}
+
+func (p *MockProvider) Store(ctx context.Context, ref string, key []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cp := make([]byte, len(key))
+	copy(cp, key)
+	p.keys[ref] = cp
+	return nil
+}
@@ -0,0 +1,79 @@
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ncw/swift/v2"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("swift", func(u *url.URL) (Provider, error) {
+		c := &swift.Connection{
+			UserName: envOrEmpty("OS_USERNAME"),
+			ApiKey:   envOrEmpty("OS_PASSWORD"),
+			AuthUrl:  envOrEmpty("OS_AUTH_URL"),
+			Tenant:   envOrEmpty("OS_TENANT_NAME"),
+		}
+		return &swiftProvider{conn: c}, nil
+	})
+}
+
+// swiftProvider stores the key, hex-encoded, as an OpenStack Swift object.
+// A ref looks like swift://container/foo, the container is the URL host and
+// the object name is the URL path.
+type swiftProvider struct {
+	conn *swift.Connection
+}
+
+func (p *swiftProvider) containerAndObject(ref string) (container, object string, err error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", "", errors.Wrap(err, "parse ref")
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+func (p *swiftProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	container, object, err := p.containerAndObject(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.conn.Authenticate(ctx); err != nil {
+		return nil, errors.Wrap(err, "authenticate to swift")
+	}
+	var buf bytes.Buffer
+	if _, err := p.conn.ObjectGet(ctx, container, object, &buf, true, nil); err != nil {
+		return nil, errors.Wrapf(err, "get %s/%s", container, object)
+	}
+	return hex.DecodeString(buf.String())
+}
+
+func (p *swiftProvider) Store(ctx context.Context, ref string, key []byte) error {
+	container, object, err := p.containerAndObject(ref)
+	if err != nil {
+		return err
+	}
+	if err := p.conn.Authenticate(ctx); err != nil {
+		return errors.Wrap(err, "authenticate to swift")
+	}
+	w, err := p.conn.ObjectCreate(ctx, container, object, false, "", "", nil)
+	if err != nil {
+		return errors.Wrapf(err, "create %s/%s", container, object)
+	}
+	if _, err := io.WriteString(w, hex.EncodeToString(key)); err != nil {
+		_ = w.Close()
+		return errors.Wrapf(err, "write %s/%s", container, object)
+	}
+	return w.Close()
+}
+
+func envOrEmpty(name string) string {
+	return os.Getenv(name)
+}
@@ -0,0 +1,47 @@
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ncw/swift/v2"
+	"github.com/ncw/swift/v2/swifttest"
+)
+
+func TestSwiftProviderRoundTripsAgainstAnInMemoryServer(t *testing.T) {
+	srv, err := swifttest.NewSwiftServer("localhost")
+	if err != nil {
+		t.Fatalf("start fake swift server: %v", err)
+	}
+	defer srv.Close()
+
+	conn := &swift.Connection{
+		UserName: "swifttest",
+		ApiKey:   "swifttest",
+		AuthUrl:  srv.AuthURL,
+	}
+	p := &swiftProvider{conn: conn}
+
+	ctx := context.Background()
+	if err := conn.Authenticate(ctx); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if err := conn.ContainerCreate(ctx, "sm4-keys", nil); err != nil {
+		t.Fatalf("create container: %v", err)
+	}
+
+	ref := "swift://sm4-keys/foo"
+	key := bytes.Repeat([]byte{0x17}, KeySize)
+
+	if err := p.Store(ctx, ref, key); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	got, err := p.Fetch(ctx, ref)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Fatalf("fetched key = %x, want %x", got, key)
+	}
+}
@@ -0,0 +1,72 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register("vault", func(u *url.URL) (Provider, error) {
+		client, err := api.NewClient(api.DefaultConfig())
+		if err != nil {
+			return nil, errors.Wrap(err, "create vault client")
+		}
+		return &vaultProvider{client: client}, nil
+	})
+}
+
+// vaultProvider stores the key, hex-encoded, under the "key" field of a
+// HashiCorp Vault KV secret. A ref looks like vault://secret/data/sm4/foo,
+// where the host+path after the scheme is the secret path passed to Vault.
+type vaultProvider struct {
+	client *api.Client
+}
+
+func (p *vaultProvider) secretPath(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "parse ref")
+	}
+	return strings.TrimPrefix(u.Host+u.Path, "/"), nil
+}
+
+func (p *vaultProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	path, err := p.secretPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := p.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q", path)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.Errorf("no secret found at %q", path)
+	}
+	data, _ := secret.Data["data"].(map[string]interface{})
+	if data == nil {
+		data = secret.Data
+	}
+	v, _ := data["key"].(string)
+	if v == "" {
+		return nil, errors.Errorf("secret at %q has no \"key\" field", path)
+	}
+	return hex.DecodeString(v)
+}
+
+func (p *vaultProvider) Store(ctx context.Context, ref string, key []byte) error {
+	path, err := p.secretPath(ref)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Logical().WriteWithContext(ctx, path, map[string]interface{}{
+		"data": map[string]interface{}{
+			"key": hex.EncodeToString(key),
+		},
+	})
+	return errors.Wrapf(err, "write %q", path)
+}
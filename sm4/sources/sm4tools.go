@@ -3,10 +3,12 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	flag "github.com/spf13/pflag"
+	"github.com/sharework-cn/smtools/sm4/sources/keyprovider"
 	"github.com/tjfoc/gmsm/sm3"
 	"github.com/tjfoc/gmsm/sm4"
 	"go.uber.org/zap"
@@ -35,8 +37,16 @@ func main() {
 	// setup handler for SIG_TERM, SIG_KILL, etc.
 	setupCloseHandler()
 
+	// "smtools keygen ..." is a distinct subcommand with its own flags
+	if len(os.Args) > 1 && os.Args[1] == "keygen" {
+		keygenMain(os.Args[2:])
+		return
+	}
+
 	// setup flags
 	key := flag.StringP("key", "k", "", `key used for SM4 algorithm`)
+	keyRef := flag.String("key-ref", "",
+		`resolve the key through a keyprovider backend instead of --key, e.g. vault://secret/data/sm4/foo`)
 	decrypt := flag.BoolP("decrypt", "d", false, "decrypt the data rather than encrypt it")
 	force := flag.BoolP("force", "f", false,
 		"force proceed for the files those already encrypted")
@@ -46,6 +56,7 @@ func main() {
 	flag.Usage = func() {
 		_, err := fmt.Fprintf(os.Stderr, `Command line tool for SM4 encryption/decryption
 Usage: smtools [options...] <file>
+       smtools keygen [options...]
 Options`)
 		if err != nil {
 			return
@@ -67,7 +78,17 @@ Options`)
 	}
 	log := l
 
-	// determine the key
+	// determine the key, preferring a provider-resolved ref over the raw flag
+	resolvedKey := []byte(*key)
+	if *keyRef != "" {
+		resolvedKey, err = keyprovider.Fetch(context.Background(), *keyRef)
+		if err != nil {
+			log.Fatalf("failed to resolve key from %q:\n%+v\n", *keyRef, err)
+			return
+		}
+		key = new(string)
+		*key = string(resolvedKey)
+	}
 	if len(*key) != 16 {
 		log.Fatal("key length is invalid, should be 16")
 		return
@@ -223,3 +244,43 @@ func setupCloseHandler() {
 		os.Exit(0)
 	}()
 }
+
+// keygenMain implements "smtools keygen": generate a random 16-byte SM4 key
+// and store it through a keyprovider backend.
+func keygenMain(args []string) {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	keyRef := fs.String("key-ref", "", `where to store the generated key, e.g. vault://secret/data/sm4/foo`)
+	logLevel := fs.String("log-level", "info", "log level(fatal/error/warn/info/debug)")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Generate and store a random SM4 key
+Usage: smtools keygen --key-ref <ref>
+Options
+`)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return
+	}
+
+	log, err := createLogger(*logLevel)
+	if err != nil {
+		stdlog.Fatalf("failed to create logger! \n%+v\n", err)
+		return
+	}
+
+	if *keyRef == "" {
+		log.Fatal("--key-ref is required")
+		return
+	}
+
+	key, err := keyprovider.Generate()
+	if err != nil {
+		log.Fatalf("failed to generate key:\n%+v\n", err)
+		return
+	}
+	if err := keyprovider.Store(context.Background(), *keyRef, key); err != nil {
+		log.Fatalf("failed to store key at %q:\n%+v\n", *keyRef, err)
+		return
+	}
+	log.Infof("stored a new key at %q", *keyRef)
+}